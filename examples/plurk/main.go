@@ -7,7 +7,6 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
-	"net/url"
 
 	"github.com/garyburd/go-oauth/oauth"
 )
@@ -102,12 +101,8 @@ func CallAPI(token *oauth.Credentials, _url string, opt map[string]string) ([]by
 
 func callAPI_(token *oauth.Credentials, _url string, opt map[string]string) ([]byte, error) {
 	var apiURL = baseURL + _url
-	param := make(url.Values)
-	for k, v := range opt {
-		param.Set(k, v)
-	}
-	oauthClient.SignParam(token, "POST", apiURL, param)
-	res, err := http.PostForm(apiURL, url.Values(param))
+	param := oauth.Params(opt)
+	res, err := oauthClient.Post(nil, token, apiURL, param)
 	if err != nil {
 		log.Println("failed to call API:", err, apiURL, param)
 		return nil, err
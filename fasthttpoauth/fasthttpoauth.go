@@ -0,0 +1,58 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fasthttpoauth signs github.com/valyala/fasthttp requests using the
+// oauth package.
+package fasthttpoauth // import "github.com/garyburd/go-oauth/fasthttpoauth"
+
+import (
+	"bytes"
+	"net/url"
+
+	"github.com/garyburd/go-oauth/oauth"
+	"github.com/valyala/fasthttp"
+)
+
+// SetAuthorizationHeader adds an OAuth signature to the request header. The
+// parameters in the request URI query string are included in the signature.
+// If the request body has content type application/x-www-form-urlencoded,
+// then the parameters in the body are also included in the signature.
+func SetAuthorizationHeader(c *oauth.Client, credentials *oauth.Credentials, req *fasthttp.Request) error {
+	method, u, form, err := requestParams(req)
+	if err != nil {
+		return err
+	}
+	v, err := c.AuthorizationHeaderValue(credentials, method, u, form)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", v)
+	return nil
+}
+
+// requestParams returns the method, URL and form body of req.
+func requestParams(req *fasthttp.Request) (string, *url.URL, url.Values, error) {
+	u, err := url.Parse(string(req.URI().FullURI()))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var form url.Values
+	if bytes.HasPrefix(req.Header.ContentType(), []byte("application/x-www-form-urlencoded")) {
+		form = make(url.Values)
+		req.PostArgs().VisitAll(func(k, v []byte) {
+			form.Add(string(k), string(v))
+		})
+	}
+	return string(req.Header.Method()), u, form, nil
+}
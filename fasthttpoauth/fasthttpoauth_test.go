@@ -0,0 +1,63 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package fasthttpoauth
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRequestParams(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetMethod("POST")
+	req.SetRequestURI("https://api.example.com/1/update?include=all")
+	req.Header.SetContentType("application/x-www-form-urlencoded")
+	req.SetBodyString("status=hello%20world&tag=a&tag=b")
+
+	method, u, form, err := requestParams(req)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if method != "POST" {
+		t.Errorf("method %q, want %q", method, "POST")
+	}
+	if want := "https://api.example.com/1/update?include=all"; u.String() != want {
+		t.Errorf("url %q, want %q", u, want)
+	}
+	if want := (url.Values{"status": {"hello world"}, "tag": {"a", "b"}}); !reflect.DeepEqual(form, want) {
+		t.Errorf("form %v, want %v", form, want)
+	}
+}
+
+func TestSetAuthorizationHeader(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("http://example.com/")
+
+	c := oauth.Client{Credentials: oauth.Credentials{Token: "key", Secret: "secret"}, SignatureMethod: oauth.PLAINTEXT}
+	if err := SetAuthorizationHeader(&c, &oauth.Credentials{Token: "accesskey", Secret: "accesssecret"}, req); err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	auth := string(req.Header.Peek("Authorization"))
+	if !strings.HasPrefix(auth, "OAuth ") || !strings.Contains(auth, `oauth_signature="secret%26accesssecret"`) {
+		t.Errorf("Authorization header %q is not signed", auth)
+	}
+}
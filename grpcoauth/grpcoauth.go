@@ -0,0 +1,79 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package grpcoauth adds OAuth signatures to gRPC calls.
+//
+// The PerRPCCredentials type implements the PerRPCCredentials interface from
+// google.golang.org/grpc/credentials. This package does not import gRPC.
+//
+//	conn, err := grpc.Dial(addr,
+//		grpc.WithTransportCredentials(creds),
+//		grpc.WithPerRPCCredentials(&grpcoauth.PerRPCCredentials{
+//			Client:      &oauthClient,
+//			Credentials: tokenCred,
+//		}))
+package grpcoauth // import "github.com/garyburd/go-oauth/grpcoauth"
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// PerRPCCredentials signs gRPC calls. The signature is computed over the
+// request method and the URI of the service. The signature is sent in the
+// authorization metadata entry.
+type PerRPCCredentials struct {
+	// Client is the OAuth client used to sign the calls.
+	Client *oauth.Client
+
+	// Credentials specifies the token credentials.
+	Credentials *oauth.Credentials
+
+	// Method is the HTTP method used in the signature. If this field is the
+	// empty string, then POST is used.
+	Method string
+
+	// AllowInsecure specifies whether the credentials can be sent over an
+	// insecure connection.
+	AllowInsecure bool
+}
+
+// GetRequestMetadata returns the authorization metadata for a call to the
+// service at uri[0].
+func (c *PerRPCCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if len(uri) == 0 {
+		return nil, errors.New("grpcoauth: service URI not provided")
+	}
+	u, err := url.Parse(uri[0])
+	if err != nil {
+		return nil, err
+	}
+	method := c.Method
+	if method == "" {
+		method = "POST"
+	}
+	v, err := c.Client.AuthorizationHeaderValue(c.Credentials, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": v}, nil
+}
+
+// RequireTransportSecurity returns true unless AllowInsecure is set.
+func (c *PerRPCCredentials) RequireTransportSecurity() bool {
+	return !c.AllowInsecure
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package grpcoauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// perRPCCredentials is a copy of the interface in
+// google.golang.org/grpc/credentials.
+type perRPCCredentials interface {
+	GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error)
+	RequireTransportSecurity() bool
+}
+
+var _ perRPCCredentials = &PerRPCCredentials{}
+
+func TestGetRequestMetadata(t *testing.T) {
+	c := &PerRPCCredentials{
+		Client:      &oauth.Client{Credentials: oauth.Credentials{Token: "key", Secret: "secret"}, SignatureMethod: oauth.PLAINTEXT},
+		Credentials: &oauth.Credentials{Token: "accesskey", Secret: "accesssecret"},
+	}
+	md, err := c.GetRequestMetadata(context.Background(), "https://api.example.com/example.Service")
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	want := `OAuth oauth_consumer_key="key", oauth_signature="secret%26accesssecret", oauth_signature_method="PLAINTEXT", oauth_token="accesskey", oauth_version="1.0"`
+	if md["authorization"] != want {
+		t.Errorf("authorization =\n      %s\nwant: %s", md["authorization"], want)
+	}
+	if !c.RequireTransportSecurity() {
+		t.Error("RequireTransportSecurity() = false, want true")
+	}
+	if _, err := c.GetRequestMetadata(context.Background()); err == nil {
+		t.Error("error should not be nil when URI is not provided")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package login
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CallbackURLBuilder builds absolute callback URLs from incoming requests.
+type CallbackURLBuilder struct {
+	// AllowedHosts is the list of hosts that can appear in a callback URL.
+	// A host includes the port when the port is not the default for the
+	// scheme. The list must not be empty. The list prevents attackers from
+	// using the Host header to redirect the provider's callback to another
+	// site.
+	AllowedHosts []string
+
+	// TrustForwardedHeaders specifies that the scheme and host are read from
+	// the X-Forwarded-Proto and X-Forwarded-Host headers when present. Set
+	// this field only when the application runs behind a proxy that sets
+	// these headers.
+	TrustForwardedHeaders bool
+}
+
+// Build returns the absolute URL for path using the scheme and host of the
+// request r. Build returns an error if the host is not allowed.
+func (b *CallbackURLBuilder) Build(r *http.Request, path string) (string, error) {
+	if len(b.AllowedHosts) == 0 {
+		return "", errors.New("login: CallbackURLBuilder.AllowedHosts is empty")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("login: callback path %q is not absolute", path)
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if b.TrustForwardedHeaders {
+		if v := forwardedValue(r.Header.Get("X-Forwarded-Proto")); v != "" {
+			scheme = strings.ToLower(v)
+		}
+		if v := forwardedValue(r.Header.Get("X-Forwarded-Host")); v != "" {
+			host = v
+		}
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("login: unsupported callback scheme %q", scheme)
+	}
+	host = strings.ToLower(host)
+	u, err := url.Parse(scheme + "://" + host + "/")
+	if err != nil || host == "" || u.Host != host || u.User != nil {
+		return "", fmt.Errorf("login: invalid callback host %q", host)
+	}
+	if !b.allowed(host) {
+		return "", errors.New("login: callback host " + host + " not allowed")
+	}
+	return scheme + "://" + host + path, nil
+}
+
+func (b *CallbackURLBuilder) allowed(host string) bool {
+	for _, h := range b.AllowedHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedValue returns the first value in a comma separated list of
+// forwarded values.
+func forwardedValue(s string) string {
+	if i := strings.IndexByte(s, ','); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
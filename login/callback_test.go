@@ -0,0 +1,76 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package login
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+var callbackURLTests = []struct {
+	builder CallbackURLBuilder
+	url     string
+	header  map[string]string
+	want    string // empty if error expected
+}{
+	{CallbackURLBuilder{}, "http://example.com/login", nil, ""},
+	{CallbackURLBuilder{AllowedHosts: []string{"example.com"}}, "http://example.com/login", nil, "http://example.com/callback"},
+	{CallbackURLBuilder{AllowedHosts: []string{"example.com"}}, "https://example.com/login", nil, "https://example.com/callback"},
+	{CallbackURLBuilder{AllowedHosts: []string{"example.com:8080"}}, "http://example.com:8080/login", nil, "http://example.com:8080/callback"},
+	{CallbackURLBuilder{AllowedHosts: []string{"example.com"}}, "http://evil.com/login", nil, ""},
+	{CallbackURLBuilder{AllowedHosts: []string{"example.com"}}, "http://Example.com/login", nil, "http://example.com/callback"},
+	{
+		CallbackURLBuilder{AllowedHosts: []string{"internal:8080"}},
+		"http://internal:8080/login",
+		map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "example.com"},
+		"http://internal:8080/callback",
+	},
+	{
+		CallbackURLBuilder{TrustForwardedHeaders: true, AllowedHosts: []string{"example.com"}},
+		"http://internal:8080/login",
+		map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "example.com, internal"},
+		"https://example.com/callback",
+	},
+	{
+		CallbackURLBuilder{TrustForwardedHeaders: true, AllowedHosts: []string{"example.com"}},
+		"http://example.com/login",
+		map[string]string{"X-Forwarded-Host": "evil.com/path"},
+		"",
+	},
+	{
+		CallbackURLBuilder{TrustForwardedHeaders: true, AllowedHosts: []string{"example.com"}},
+		"http://example.com/login",
+		map[string]string{"X-Forwarded-Proto": "javascript"},
+		"",
+	},
+}
+
+func TestCallbackURLBuilder(t *testing.T) {
+	for _, tt := range callbackURLTests {
+		r := httptest.NewRequest("GET", tt.url, nil)
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+		got, err := tt.builder.Build(r, "/callback")
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("Build(%s, %v) = %q, want error", tt.url, tt.header, got)
+		case tt.want != "" && err != nil:
+			t.Errorf("Build(%s, %v) returned error %v", tt.url, tt.header, err)
+		case got != tt.want:
+			t.Errorf("Build(%s, %v) = %q, want %q", tt.url, tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,581 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package login implements sign in with one or more OAuth providers.
+//
+// A Manager handles the requests /login/{provider} and /callback/{provider}.
+// The login request gets temporary credentials from the provider and
+// redirects the user to the provider's authorization page. The callback
+// request exchanges the temporary credentials for token credentials and
+// calls the application's Success function with the resulting Identity.
+//
+//	m := &login.Manager{
+//		Providers: map[string]*login.Provider{
+//			"twitter": {Client: &twitterClient},
+//			"tumblr":  {Client: &tumblrClient},
+//		},
+//		CallbackURL: &login.CallbackURLBuilder{AllowedHosts: []string{"example.com"}},
+//		Success: func(w http.ResponseWriter, r *http.Request, id *login.Identity) {
+//			// Save id.Credentials and redirect to the application.
+//		},
+//	}
+//	http.Handle("/login/", m)
+//	http.Handle("/callback/", m)
+package login // import "github.com/garyburd/go-oauth/login"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+var (
+	// ErrUnknownToken is returned when the temporary credentials for a
+	// callback request are not found.
+	ErrUnknownToken = errors.New("login: unknown oauth_token")
+
+	// ErrUserDenied is returned when the user denies the authorization
+	// request at the provider.
+	ErrUserDenied = errors.New("login: user denied authorization")
+)
+
+// CallbackRejectedError is returned when the provider rejects the callback
+// URL. Providers that require a registered callback URL reject URLs that do
+// not match exactly. Check that the URL matches the registered URL and set
+// Provider.ExactCallbackURL if the provider rejects callback URLs with a
+// query string.
+type CallbackRejectedError struct {
+	URL string
+	Err error
+}
+
+func (e *CallbackRejectedError) Error() string {
+	return "login: provider rejected callback URL " + e.URL + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the provider.
+func (e *CallbackRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// Provider configures sign in with a provider.
+type Provider struct {
+	// Client is the OAuth client for the provider. Set the client Audit
+	// field to record the token credentials issued by sign in.
+	Client *oauth.Client
+
+	// AuthorizationParams specifies additional parameters for the
+	// authorization URL. These parameters are not signed.
+	AuthorizationParams url.Values
+
+	// TemporaryCredentialParams specifies additional parameters for the
+	// signed temporary credentials request.
+	TemporaryCredentialParams url.Values
+
+	// VerifyCredentialsURL is the URL of the provider's endpoint for getting
+	// information about the user. If this field is set, then the manager
+	// sends a signed GET request to the URL after getting the token
+	// credentials and sets the Identity User field from the response.
+	VerifyCredentialsURL string
+
+	// ParseUser parses the response body from VerifyCredentialsURL. If this
+	// field is nil, then ParseJSONUser is used.
+	ParseUser func(body []byte) (*UserInfo, error)
+
+	// ExactCallbackURL specifies that the provider requires the callback URL
+	// to exactly match a registered URL. If this field is set, then callback
+	// parameters are saved in the store with the temporary credentials
+	// instead of in the callback URL.
+	ExactCallbackURL bool
+}
+
+// UserInfo is the normalized information about a user.
+type UserInfo struct {
+	ID          string
+	Username    string
+	DisplayName string
+}
+
+// ParseJSONUser parses a JSON object with user information. The ID is read
+// from the "id_str" or "id" field, the username from the "screen_name" or
+// "username" field and the display name from the "name" or "display_name"
+// field.
+func ParseJSONUser(body []byte) (*UserInfo, error) {
+	var m map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, err
+	}
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			switch v := m[k].(type) {
+			case string:
+				if v != "" {
+					return v
+				}
+			case json.Number:
+				return v.String()
+			}
+		}
+		return ""
+	}
+	return &UserInfo{
+		ID:          get("id_str", "id"),
+		Username:    get("screen_name", "username"),
+		DisplayName: get("name", "display_name"),
+	}, nil
+}
+
+// Identity is the result of a successful sign in.
+type Identity struct {
+	// Provider is the name of the provider.
+	Provider string
+
+	// ConsumerKey is the client credentials token of the provider
+	// configuration. Multi-tenant applications save the consumer key with
+	// the token credentials to find the client for the credentials.
+	ConsumerKey string
+
+	// Credentials are the token credentials returned by the provider.
+	Credentials *oauth.Credentials
+
+	// User is information about the user. The ID and Username fields are set
+	// from the token response when the provider includes this information in
+	// the response. All fields are set from the provider's verify credentials
+	// endpoint when the Provider VerifyCredentialsURL field is set.
+	User UserInfo
+
+	// Values is the token response.
+	Values url.Values
+
+	// CallbackParams are the parameters named in the Manager CallbackParams
+	// field from the login request.
+	CallbackParams url.Values
+}
+
+var (
+	userIDKeys   = []string{"user_id", "user_nsid", "xoauth_yahoo_guid", "encoded_user_id"}
+	usernameKeys = []string{"screen_name", "username"}
+)
+
+func firstValue(v url.Values, keys []string) string {
+	for _, k := range keys {
+		if s := v.Get(k); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// TempCredentialStore stores temporary credentials and callback parameters
+// between the login request and the callback request.
+type TempCredentialStore interface {
+	// Put stores the credentials and parameters keyed by the credentials
+	// token.
+	Put(credentials *oauth.Credentials, params url.Values) error
+
+	// Take returns and deletes the credentials and parameters with the given
+	// token. Take returns ErrUnknownToken if the credentials are not found.
+	Take(token string) (*oauth.Credentials, url.Values, error)
+}
+
+// DefaultTTL is the default time to live for credentials in a MemoryStore.
+const DefaultTTL = 15 * time.Minute
+
+// MemoryStore is a TempCredentialStore that stores credentials in memory.
+// Credentials expire after a time to live so that abandoned sign in attempts
+// do not use memory forever. Expired credentials are deleted lazily. The zero
+// value is ready to use.
+type MemoryStore struct {
+	// TTL is the time to live for credentials. If this field is zero, then
+	// DefaultTTL is used.
+	TTL time.Duration
+
+	mu        sync.Mutex
+	m         map[string]memoryEntry
+	nextSweep time.Time
+}
+
+type memoryEntry struct {
+	credentials *oauth.Credentials
+	params      url.Values
+	expires     time.Time
+}
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+func (s *MemoryStore) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return DefaultTTL
+}
+
+// Put implements the TempCredentialStore interface.
+func (s *MemoryStore) Put(credentials *oauth.Credentials, params url.Values) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timeNow()
+	if s.m == nil {
+		s.m = make(map[string]memoryEntry)
+	}
+	if now.After(s.nextSweep) {
+		for k, e := range s.m {
+			if now.After(e.expires) {
+				delete(s.m, k)
+			}
+		}
+		s.nextSweep = now.Add(s.ttl())
+	}
+	s.m[credentials.Token] = memoryEntry{credentials: credentials, params: params, expires: now.Add(s.ttl())}
+	return nil
+}
+
+// Take implements the TempCredentialStore interface.
+func (s *MemoryStore) Take(token string) (*oauth.Credentials, url.Values, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.m[token]
+	if !ok {
+		return nil, nil, ErrUnknownToken
+	}
+	delete(s.m, token)
+	if timeNow().After(e.expires) {
+		return nil, nil, ErrUnknownToken
+	}
+	return e.credentials, e.params, nil
+}
+
+// Len returns the number of credentials in the store, including expired
+// credentials that are not deleted yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.m)
+}
+
+// Manager handles login and callback requests for a set of providers.
+type Manager struct {
+	// Providers maps provider names to provider configurations.
+	Providers map[string]*Provider
+
+	// ProviderFunc returns the provider configuration for a login or callback
+	// request. The name is the part of the path after the login or callback
+	// path prefix. Multi-tenant applications with a consumer key for each
+	// tenant use this field to select the client for the tenant, for example
+	// from a name of the form "{tenant}/{provider}" or from the request host.
+	// ProviderFunc must return the same configuration for the login and
+	// callback requests of a sign in. Return nil if the provider is not
+	// found. If this field is nil, then the Providers map is used.
+	ProviderFunc func(r *http.Request, name string) (*Provider, error)
+
+	// Store stores temporary credentials. If this field is nil, then the
+	// credentials are stored in memory.
+	Store TempCredentialStore
+
+	// HTTPClient is used to send requests to the providers. If this field is
+	// nil, then the oauth package default client is used.
+	HTTPClient oauth.Doer
+
+	// LoginPath and CallbackPath are the path prefixes for login and callback
+	// requests. The provider name follows the prefix. The default values are
+	// "/login/" and "/callback/".
+	LoginPath    string
+	CallbackPath string
+
+	// CallbackURL builds the callback URL sent to the provider. This field
+	// must be set with a non-empty list of allowed hosts.
+	CallbackURL *CallbackURLBuilder
+
+	// CallbackParams is the list of login request query parameters that are
+	// passed through to the callback request, for example a path to return
+	// to after sign in. The parameters are added to the callback URL or saved
+	// in the store when the provider requires an exact callback URL. The
+	// application should validate the values before use.
+	CallbackParams []string
+
+	// SessionState saves a single-use state token for the browser session
+	// that starts a sign in. The callback request fails with
+	// ErrStateMismatch when the request is not from the same browser
+	// session. If this field is nil, then the token is stored in a secure
+	// cookie using the CookieState defaults.
+	SessionState SessionState
+
+	// Success is called with the identity after a successful sign in. This
+	// field must be set.
+	Success func(w http.ResponseWriter, r *http.Request, id *Identity)
+
+	// Error is called when sign in fails. If this field is nil, then the
+	// error is reported to the user with status 500.
+	Error func(w http.ResponseWriter, r *http.Request, err error)
+
+	memoryStore MemoryStore
+}
+
+func (m *Manager) store() TempCredentialStore {
+	if m.Store != nil {
+		return m.Store
+	}
+	return &m.memoryStore
+}
+
+func (m *Manager) loginPath() string {
+	if m.LoginPath == "" {
+		return "/login/"
+	}
+	return m.LoginPath
+}
+
+func (m *Manager) callbackPath() string {
+	if m.CallbackPath == "" {
+		return "/callback/"
+	}
+	return m.CallbackPath
+}
+
+func (m *Manager) context(r *http.Request) context.Context {
+	ctx := r.Context()
+	if m.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth.HTTPClient, m.HTTPClient)
+	}
+	return ctx
+}
+
+func (m *Manager) error(w http.ResponseWriter, r *http.Request, err error) {
+	if m.Error != nil {
+		m.Error(w, r, err)
+		return
+	}
+	http.Error(w, "Error signing in, "+err.Error(), http.StatusInternalServerError)
+}
+
+// ServeHTTP routes login and callback requests.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var handler func(http.ResponseWriter, *http.Request, string, *Provider)
+	var name string
+	switch {
+	case strings.HasPrefix(r.URL.Path, m.loginPath()):
+		handler = m.serveLogin
+		name = r.URL.Path[len(m.loginPath()):]
+	case strings.HasPrefix(r.URL.Path, m.callbackPath()):
+		handler = m.serveCallback
+		name = r.URL.Path[len(m.callbackPath()):]
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	p := m.Providers[name]
+	if m.ProviderFunc != nil {
+		var err error
+		p, err = m.ProviderFunc(r, name)
+		if err != nil {
+			m.error(w, r, err)
+			return
+		}
+	}
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+	handler(w, r, name, p)
+}
+
+// defaultSessionState is used when Manager.SessionState is nil.
+var defaultSessionState SessionState = &CookieState{}
+
+func (m *Manager) sessionState() SessionState {
+	if m.SessionState != nil {
+		return m.SessionState
+	}
+	return defaultSessionState
+}
+
+// callbackURL returns the absolute URL of the callback for provider name.
+func (m *Manager) callbackURL(r *http.Request, name string) (string, error) {
+	if m.CallbackURL == nil {
+		return "", errors.New("login: Manager.CallbackURL is nil")
+	}
+	return m.CallbackURL.Build(r, m.callbackPath()+name)
+}
+
+func (m *Manager) serveLogin(w http.ResponseWriter, r *http.Request, name string, p *Provider) {
+	ctx := m.context(r)
+	callbackURL, err := m.callbackURL(r, name)
+	if err != nil {
+		m.error(w, r, err)
+		return
+	}
+	params := CallbackParams(r, m.CallbackParams)
+	var storeParams url.Values
+	if p.ExactCallbackURL {
+		storeParams = params
+	} else if len(params) > 0 {
+		callbackURL += "?" + params.Encode()
+	}
+	flow, state, err := newState()
+	if err != nil {
+		m.error(w, r, err)
+		return
+	}
+	if err := m.sessionState().Set(w, r, flow, state); err != nil {
+		m.error(w, r, err)
+		return
+	}
+	if storeParams == nil {
+		storeParams = url.Values{}
+	}
+	storeParams.Set(stateParam, flow+"."+state)
+	tempCred, err := p.Client.RequestTemporaryCredentialsContext(ctx, callbackURL, p.TemporaryCredentialParams)
+	if err != nil {
+		if oauth.IsCallbackRejected(err) {
+			err = &CallbackRejectedError{URL: callbackURL, Err: err}
+		}
+		m.error(w, r, err)
+		return
+	}
+	if err := m.store().Put(tempCred, storeParams); err != nil {
+		m.error(w, r, err)
+		return
+	}
+	http.Redirect(w, r, p.Client.AuthorizationURL(tempCred, p.AuthorizationParams), http.StatusFound)
+}
+
+// CallbackParams returns the query parameters in r with the given names.
+// Parameters with the "oauth_" prefix are not returned because the provider
+// can add these parameters to the callback request.
+func CallbackParams(r *http.Request, names []string) url.Values {
+	params := url.Values{}
+	q := r.URL.Query()
+	for _, name := range names {
+		if strings.HasPrefix(name, "oauth_") {
+			continue
+		}
+		if v, ok := q[name]; ok {
+			params[name] = v
+		}
+	}
+	return params
+}
+
+// userDenied returns true if the callback request shows that the user denied
+// the authorization request. Twitter sets the denied parameter, providers
+// that implement problem reporting set oauth_problem to user_refused and
+// other providers omit the verifier.
+func userDenied(r *http.Request, verifierParam string) bool {
+	return r.FormValue("denied") != "" ||
+		oauth.Problem(r.FormValue("oauth_problem")) == oauth.ProblemUserRefused ||
+		r.FormValue(verifierParam) == ""
+}
+
+// verifierParam returns the name of the verifier parameter in the callback
+// request.
+func verifierParam(c *oauth.Client) string {
+	if c.VerifierParam != "" {
+		return c.VerifierParam
+	}
+	return "oauth_verifier"
+}
+
+func (m *Manager) serveCallback(w http.ResponseWriter, r *http.Request, name string, p *Provider) {
+	verifierName := verifierParam(p.Client)
+	if userDenied(r, verifierName) {
+		token := r.FormValue("denied")
+		if token == "" {
+			token = r.FormValue("oauth_token")
+		}
+		m.store().Take(token)
+		m.error(w, r, ErrUserDenied)
+		return
+	}
+	tempCred, params, err := m.store().Take(r.FormValue("oauth_token"))
+	if err != nil {
+		m.error(w, r, err)
+		return
+	}
+	flow, want := splitState(params.Get(stateParam))
+	state, err := m.sessionState().Take(w, r, flow)
+	if err != nil {
+		m.error(w, r, err)
+		return
+	}
+	if !stateEqual(state, want) {
+		m.error(w, r, ErrStateMismatch)
+		return
+	}
+	delete(params, stateParam)
+	if !p.ExactCallbackURL {
+		params = CallbackParams(r, m.CallbackParams)
+	}
+	ctx := m.context(r)
+	tokenCred, values, err := p.Client.RequestTokenContext(ctx, tempCred, r.FormValue(verifierName))
+	if err != nil {
+		m.error(w, r, err)
+		return
+	}
+	id := &Identity{
+		Provider:    name,
+		ConsumerKey: p.Client.Credentials.Token,
+		Credentials: tokenCred,
+		User: UserInfo{
+			ID:       firstValue(values, userIDKeys),
+			Username: firstValue(values, usernameKeys),
+		},
+		Values:         values,
+		CallbackParams: params,
+	}
+	if p.VerifyCredentialsURL != "" {
+		user, err := fetchUser(ctx, p, tokenCred)
+		if err != nil {
+			m.error(w, r, err)
+			return
+		}
+		id.User = *user
+	}
+	m.Success(w, r, id)
+}
+
+// fetchUser gets the user information from the provider's verify credentials
+// endpoint.
+func fetchUser(ctx context.Context, p *Provider, credentials *oauth.Credentials) (*UserInfo, error) {
+	resp, err := p.Client.GetContext(ctx, credentials, p.VerifyCredentialsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oauth.DefaultMaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("login: verify credentials returned status %d, %s", resp.StatusCode, body)
+	}
+	parse := p.ParseUser
+	if parse == nil {
+		parse = ParseJSONUser
+	}
+	return parse(body)
+}
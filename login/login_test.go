@@ -0,0 +1,425 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package login
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// newTestProvider returns a server that implements the temporary credentials
+// and token endpoints of a provider.
+func newTestProvider(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/request_token":
+			if !strings.Contains(auth, "oauth_callback=") {
+				t.Errorf("oauth_callback missing from %q", auth)
+			}
+			io.WriteString(w, "oauth_token=temp&oauth_token_secret=tempsecret&oauth_callback_confirmed=true")
+		case "/access_token":
+			if !strings.Contains(auth, `oauth_verifier="verifier"`) && r.FormValue("pin") != "verifier" {
+				t.Errorf("verifier missing from %q and form", auth)
+			}
+			io.WriteString(w, "oauth_token=token&oauth_token_secret=secret&user_id=1234&screen_name=gopher")
+		case "/verify_credentials":
+			if !strings.Contains(auth, `oauth_token="token"`) {
+				t.Errorf("oauth_token missing from %q", auth)
+			}
+			io.WriteString(w, `{"id": 1234, "screen_name": "gopher", "name": "Gopher"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// testCallbackURL allows the host used by the test requests.
+var testCallbackURL = &CallbackURLBuilder{AllowedHosts: []string{"example.com"}}
+
+func newTestClient(urlStr string) *oauth.Client {
+	return &oauth.Client{
+		TemporaryCredentialRequestURI: urlStr + "/request_token",
+		ResourceOwnerAuthorizationURI: urlStr + "/authorize",
+		TokenRequestURI:               urlStr + "/access_token",
+	}
+}
+
+// signIn sends a login request to m and then a callback request with the
+// cookies from the login response. signIn returns the login response.
+func signIn(m *Manager, loginURL, callbackURL string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", loginURL, nil))
+	r := httptest.NewRequest("GET", callbackURL, nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	m.ServeHTTP(httptest.NewRecorder(), r)
+	return w
+}
+
+func TestManager_CallbackURLRequired(t *testing.T) {
+	var gotErr error
+	m := &Manager{
+		Providers: map[string]*Provider{"test": {Client: newTestClient("http://provider.example.com")}},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			gotErr = err
+		},
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/login/test", nil))
+	if gotErr == nil {
+		t.Fatal("login without CallbackURL succeeded, want error")
+	}
+}
+
+func TestManager(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	var id *Identity
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			id = i
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Errorf("sign in failed, %v", err)
+		},
+	}
+
+	w := signIn(m, "http://example.com/login/test", "http://example.com/callback/test?oauth_token=temp&oauth_verifier=verifier")
+	if w.Code != http.StatusFound {
+		t.Fatalf("login status %d, want %d", w.Code, http.StatusFound)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Path != "/authorize" || loc.Query().Get("oauth_token") != "temp" {
+		t.Errorf("redirect to %s, want authorization URL with temporary token", loc)
+	}
+	if id == nil {
+		t.Fatal("Success not called")
+	}
+	if id.Provider != "test" || id.Credentials.Token != "token" || id.User.ID != "1234" || id.User.Username != "gopher" {
+		t.Errorf("identity %+v, want test provider, token, 1234, gopher", id)
+	}
+}
+
+func TestManager_VerifierParam(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	c := newTestClient(ps.URL)
+	c.VerifierParam = "pin"
+	var id *Identity
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: c}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			id = i
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Errorf("sign in failed, %v", err)
+		},
+	}
+	signIn(m, "http://example.com/login/test", "http://example.com/callback/test?oauth_token=temp&pin=verifier")
+	if id == nil || id.Credentials.Token != "token" {
+		t.Errorf("identity %+v, want token", id)
+	}
+}
+
+func TestManager_UnknownToken(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	var gotErr error
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			t.Error("Success called for unknown token")
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			gotErr = err
+		},
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/callback/test?oauth_token=unknown&oauth_verifier=verifier", nil))
+	if gotErr != ErrUnknownToken {
+		t.Errorf("error %v, want %v", gotErr, ErrUnknownToken)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/other", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown provider status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestManager_VerifyCredentials(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	var id *Identity
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers: map[string]*Provider{"test": {
+			Client:               newTestClient(ps.URL),
+			VerifyCredentialsURL: ps.URL + "/verify_credentials",
+		}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			id = i
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Errorf("sign in failed, %v", err)
+		},
+	}
+	signIn(m, "http://example.com/login/test", "http://example.com/callback/test?oauth_token=temp&oauth_verifier=verifier")
+	if id == nil {
+		t.Fatal("Success not called")
+	}
+	want := UserInfo{ID: "1234", Username: "gopher", DisplayName: "Gopher"}
+	if id.User != want {
+		t.Errorf("user %+v, want %+v", id.User, want)
+	}
+}
+
+func TestManager_UserDenied(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	for _, query := range []string{
+		"denied=temp",
+		"oauth_token=temp&oauth_problem=user_refused",
+		"oauth_token=temp",
+	} {
+		var gotErr error
+		m := &Manager{
+			CallbackURL: testCallbackURL,
+			Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+			Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+				t.Errorf("Success called for %s", query)
+			},
+			Error: func(w http.ResponseWriter, r *http.Request, err error) {
+				gotErr = err
+			},
+		}
+		signIn(m, "http://example.com/login/test", "http://example.com/callback/test?"+query)
+		if gotErr != ErrUserDenied {
+			t.Errorf("error for %s is %v, want %v", query, gotErr, ErrUserDenied)
+		}
+		if _, _, err := m.store().Take("temp"); err != ErrUnknownToken {
+			t.Errorf("temporary credentials not deleted for %s", query)
+		}
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestManager_CallbackParams(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	for _, exact := range []bool{false, true} {
+		var id *Identity
+		var auth string
+		m := &Manager{
+			CallbackURL: testCallbackURL,
+			Providers: map[string]*Provider{"test": {
+				Client:           newTestClient(ps.URL),
+				ExactCallbackURL: exact,
+			}},
+			HTTPClient: doerFunc(func(r *http.Request) (*http.Response, error) {
+				if r.URL.Path == "/request_token" {
+					auth = r.Header.Get("Authorization")
+				}
+				return http.DefaultClient.Do(r)
+			}),
+			CallbackParams: []string{"return_to", "oauth_token"},
+			Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+				id = i
+			},
+			Error: func(w http.ResponseWriter, r *http.Request, err error) {
+				t.Errorf("sign in failed, %v", err)
+			},
+		}
+		callback := "http://example.com/callback/test?oauth_token=temp&oauth_verifier=verifier"
+		if !exact {
+			callback += "&return_to=%2Fhome"
+		}
+		signIn(m, "http://example.com/login/test?return_to=%2Fhome&other=x&oauth_token=x", callback)
+		wantCallback := `oauth_callback="http%3A%2F%2Fexample.com%2Fcallback%2Ftest%3Freturn_to%3D%252Fhome"`
+		if exact {
+			wantCallback = `oauth_callback="http%3A%2F%2Fexample.com%2Fcallback%2Ftest"`
+		}
+		if !strings.Contains(auth, wantCallback) {
+			t.Errorf("exact=%v, authorization %q does not contain %s", exact, auth, wantCallback)
+		}
+		if id == nil {
+			t.Fatalf("exact=%v, Success not called", exact)
+		}
+		want := url.Values{"return_to": {"/home"}}
+		if !reflect.DeepEqual(id.CallbackParams, want) {
+			t.Errorf("exact=%v, callback params %v, want %v", exact, id.CallbackParams, want)
+		}
+	}
+}
+
+func TestMemoryStore_TTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	s := &MemoryStore{TTL: time.Minute}
+	s.Put(&oauth.Credentials{Token: "a"}, nil)
+	s.Put(&oauth.Credentials{Token: "b"}, nil)
+
+	now = now.Add(30 * time.Second)
+	if _, _, err := s.Take("a"); err != nil {
+		t.Errorf("Take(a) returned error %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, err := s.Take("b"); err != ErrUnknownToken {
+		t.Errorf("Take(b) returned error %v, want %v", err, ErrUnknownToken)
+	}
+
+	s.Put(&oauth.Credentials{Token: "c"}, nil)
+	s.Put(&oauth.Credentials{Token: "d"}, nil)
+	now = now.Add(2 * time.Minute)
+	s.Put(&oauth.Credentials{Token: "e"}, nil)
+	if n := s.Len(); n != 1 {
+		t.Errorf("Len() = %d after sweep, want 1", n)
+	}
+}
+
+func TestManager_CallbackRejected(t *testing.T) {
+	ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "oauth_problem=parameter_rejected&oauth_parameters_rejected=oauth_callback")
+	}))
+	defer ps.Close()
+
+	var gotErr error
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			t.Error("Success called")
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			gotErr = err
+		},
+	}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/login/test", nil))
+	e, ok := gotErr.(*CallbackRejectedError)
+	if !ok {
+		t.Fatalf("error %v, want *CallbackRejectedError", gotErr)
+	}
+	if e.URL != "http://example.com/callback/test" {
+		t.Errorf("callback URL %s, want http://example.com/callback/test", e.URL)
+	}
+}
+
+func TestManager_ProviderParams(t *testing.T) {
+	var requestAuth string
+	ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestAuth = r.Header.Get("Authorization")
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if r.PostForm.Get("scope") != "read" {
+			t.Errorf("scope %q, want read", r.PostForm.Get("scope"))
+		}
+		io.WriteString(w, "oauth_token=temp&oauth_token_secret=tempsecret")
+	}))
+	defer ps.Close()
+
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers: map[string]*Provider{"test": {
+			Client:                    newTestClient(ps.URL),
+			TemporaryCredentialParams: url.Values{"scope": {"read"}},
+			AuthorizationParams:       url.Values{"Access": {"Full"}},
+		}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {},
+	}
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/test", nil))
+	if requestAuth == "" {
+		t.Fatal("temporary credentials request not sent")
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := loc.Query(); q.Get("Access") != "Full" || q.Get("scope") != "" {
+		t.Errorf("authorization URL %s, want Access and no scope", loc)
+	}
+}
+
+func TestManager_ProviderFunc(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	clients := map[string]*oauth.Client{}
+	for _, tenant := range []string{"acme", "globex"} {
+		c := newTestClient(ps.URL)
+		c.Credentials.Token = tenant + "-key"
+		clients[tenant] = c
+	}
+	var id *Identity
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		ProviderFunc: func(r *http.Request, name string) (*Provider, error) {
+			parts := strings.Split(name, "/")
+			if len(parts) != 2 || parts[1] != "test" || clients[parts[0]] == nil {
+				return nil, nil
+			}
+			return &Provider{Client: clients[parts[0]]}, nil
+		},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			id = i
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Errorf("sign in failed, %v", err)
+		},
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/other/test", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown tenant status %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	signIn(m, "http://example.com/login/globex/test", "http://example.com/callback/globex/test?oauth_token=temp&oauth_verifier=verifier")
+	if id == nil {
+		t.Fatal("Success not called")
+	}
+	if id.Provider != "globex/test" || id.ConsumerKey != "globex-key" {
+		t.Errorf("identity provider %s, consumer key %s, want globex/test, globex-key", id.Provider, id.ConsumerKey)
+	}
+}
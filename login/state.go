@@ -0,0 +1,136 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package login
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrStateMismatch is returned when a callback request is not from the
+// browser that started the sign in.
+var ErrStateMismatch = errors.New("login: callback request does not match browser session")
+
+// SessionState saves a single-use state token for the browser session that
+// starts a sign in. The Manager stores the token with the temporary
+// credentials and checks that the callback request is from the same browser
+// session. This prevents an attacker from completing a sign in started in
+// the attacker's browser in the victim's browser.
+//
+// The flow argument is a random identifier for the sign in. A browser can
+// have more than one sign in in progress, for example in different tabs. An
+// implementation must store the state for each flow separately so that a
+// second sign in does not replace the state of the first.
+//
+// CookieState stores the token in a cookie. Applications with server-side
+// sessions can implement this interface to store the token in the session or
+// in an encrypted cookie.
+type SessionState interface {
+	// Set saves the state of flow for the browser session of r.
+	Set(w http.ResponseWriter, r *http.Request, flow, state string) error
+
+	// Take returns and deletes the state of flow for the browser session of
+	// r. Take returns the empty string if there is no state.
+	Take(w http.ResponseWriter, r *http.Request, flow string) (string, error)
+}
+
+// CookieState is a SessionState that stores the state in a cookie. Each
+// sign in uses a separate cookie.
+type CookieState struct {
+	// Name is the prefix of the cookie name. The cookie name is the prefix,
+	// an underscore and the flow. If this field is the empty string, then
+	// "login_state" is used.
+	Name string
+
+	// Path is the cookie path. The path must include the callback path. If
+	// this field is the empty string, then "/" is used.
+	Path string
+
+	// Insecure specifies that the cookie is sent over plain HTTP
+	// connections. By default, the cookie has the Secure attribute.
+	Insecure bool
+}
+
+func (s *CookieState) name(flow string) string {
+	name := s.Name
+	if name == "" {
+		name = "login_state"
+	}
+	return name + "_" + flow
+}
+
+func (s *CookieState) cookie(flow, value string, maxAge int) *http.Cookie {
+	path := s.Path
+	if path == "" {
+		path = "/"
+	}
+	return &http.Cookie{
+		Name:     s.name(flow),
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		Secure:   !s.Insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// Set implements the SessionState interface.
+func (s *CookieState) Set(w http.ResponseWriter, r *http.Request, flow, state string) error {
+	http.SetCookie(w, s.cookie(flow, state, int(DefaultTTL.Seconds())))
+	return nil
+}
+
+// Take implements the SessionState interface.
+func (s *CookieState) Take(w http.ResponseWriter, r *http.Request, flow string) (string, error) {
+	c, err := r.Cookie(s.name(flow))
+	if err != nil {
+		return "", nil
+	}
+	http.SetCookie(w, s.cookie(flow, "", -1))
+	return c.Value, nil
+}
+
+// stateParam is the key for the flow and state in the parameters saved with
+// the temporary credentials. The value is the flow, a dot and the state. The
+// oauth_ prefix prevents conflicts with the callback parameters.
+const stateParam = "oauth_login_state"
+
+// newState returns a random flow identifier and state token.
+func newState() (string, string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(b[:8]), hex.EncodeToString(b[8:]), nil
+}
+
+// splitState returns the flow and state from the value of stateParam.
+func splitState(v string) (string, string) {
+	i := strings.IndexByte(v, '.')
+	if i < 0 {
+		return "", ""
+	}
+	return v[:i], v[i+1:]
+}
+
+// stateEqual returns true if the states are equal and not empty.
+func stateEqual(a, b string) bool {
+	return a != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package login
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestManager_SessionState(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	for _, tt := range []struct {
+		name   string
+		cookie func(c *http.Cookie) *http.Cookie
+		want   error
+	}{
+		{"same session", func(c *http.Cookie) *http.Cookie { return c }, nil},
+		{"no cookie", func(c *http.Cookie) *http.Cookie { return nil }, ErrStateMismatch},
+		{"other session", func(c *http.Cookie) *http.Cookie { return &http.Cookie{Name: c.Name, Value: "other"} }, ErrStateMismatch},
+	} {
+		var gotErr error
+		success := false
+		m := &Manager{
+			CallbackURL:  testCallbackURL,
+			Providers:    map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+			SessionState: &CookieState{Insecure: true},
+			Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+				success = true
+			},
+			Error: func(w http.ResponseWriter, r *http.Request, err error) {
+				gotErr = err
+			},
+		}
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/test", nil))
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Name, "login_state_") || cookies[0].Value == "" {
+			t.Fatalf("%s: login cookies %v, want login_state_ cookie", tt.name, cookies)
+		}
+
+		r := httptest.NewRequest("GET", "http://example.com/callback/test?oauth_token=temp&oauth_verifier=verifier", nil)
+		if c := tt.cookie(cookies[0]); c != nil {
+			r.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		if gotErr != tt.want {
+			t.Errorf("%s: error %v, want %v", tt.name, gotErr, tt.want)
+		}
+		if success != (tt.want == nil) {
+			t.Errorf("%s: Success called = %v, want %v", tt.name, success, tt.want == nil)
+		}
+	}
+}
+
+func TestManager_DefaultSessionState(t *testing.T) {
+	ps := newTestProvider(t)
+	defer ps.Close()
+
+	var gotErr error
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			t.Error("Success called without state cookie")
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			gotErr = err
+		},
+	}
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/test", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Name, "login_state_") || !cookies[0].Secure {
+		t.Fatalf("login cookies %v, want secure login_state_ cookie", cookies)
+	}
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/callback/test?oauth_token=temp&oauth_verifier=verifier", nil))
+	if gotErr != ErrStateMismatch {
+		t.Errorf("error %v, want %v", gotErr, ErrStateMismatch)
+	}
+}
+
+func TestManager_ConcurrentSignIns(t *testing.T) {
+	n := 0
+	ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/request_token":
+			n++
+			fmt.Fprintf(w, "oauth_token=temp%d&oauth_token_secret=tempsecret&oauth_callback_confirmed=true", n)
+		case "/access_token":
+			io.WriteString(w, "oauth_token=token&oauth_token_secret=secret")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ps.Close()
+
+	var signedIn []string
+	m := &Manager{
+		CallbackURL: testCallbackURL,
+		Providers:   map[string]*Provider{"test": {Client: newTestClient(ps.URL)}},
+		Success: func(w http.ResponseWriter, r *http.Request, i *Identity) {
+			signedIn = append(signedIn, r.FormValue("oauth_token"))
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Errorf("sign in with %s failed, %v", r.FormValue("oauth_token"), err)
+		},
+	}
+
+	// Start two sign ins in the same browser before completing either.
+	var jar []*http.Cookie
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/login/test", nil))
+		jar = append(jar, w.Result().Cookies()...)
+	}
+	if len(jar) != 2 || jar[0].Name == jar[1].Name {
+		t.Fatalf("login cookies %v, want two cookies with different names", jar)
+	}
+
+	for _, token := range []string{"temp1", "temp2"} {
+		r := httptest.NewRequest("GET", "http://example.com/callback/test?oauth_token="+token+"&oauth_verifier=verifier", nil)
+		for _, c := range jar {
+			r.AddCookie(c)
+		}
+		m.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if len(signedIn) != 2 {
+		t.Errorf("completed sign ins %v, want temp1 and temp2", signedIn)
+	}
+}
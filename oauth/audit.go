@@ -0,0 +1,76 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import "time"
+
+// AuditEventType is the type of a credential lifecycle event.
+type AuditEventType string
+
+// Credential lifecycle events.
+const (
+	// AuditIssued is reported when the provider issues token credentials
+	// in exchange for temporary credentials or a user name and password.
+	AuditIssued AuditEventType = "issued"
+
+	// AuditRenewed is reported when the provider renews token credentials.
+	AuditRenewed AuditEventType = "renewed"
+
+	// AuditRevoked is reported when the provider revokes token
+	// credentials.
+	AuditRevoked AuditEventType = "revoked"
+
+	// AuditVerificationFailed is reported when ValidateToken finds that the
+	// token credentials are invalid, expired or revoked.
+	AuditVerificationFailed AuditEventType = "verification_failed"
+
+	// AuditSecretRotated is reported when the provider returns a new
+	// secret for token credentials. See the Client SecretRotated field.
+	AuditSecretRotated AuditEventType = "secret_rotated"
+)
+
+// AuditEvent is a credential lifecycle event. Events do not include secrets.
+type AuditEvent struct {
+	Type AuditEventType
+
+	// Time is the time of the event.
+	Time time.Time
+
+	// ConsumerKey is the client credentials token.
+	ConsumerKey string
+
+	// Token is the token of the credentials.
+	Token string
+
+	// PreviousToken is the token of the credentials replaced by a renewal.
+	PreviousToken string
+
+	// Err is the reason for a verification failure.
+	Err error
+}
+
+func (c *Client) audit(typ AuditEventType, token, previousToken string, err error) {
+	if c.Audit == nil {
+		return
+	}
+	c.Audit(AuditEvent{
+		Type:          typ,
+		Time:          c.now(),
+		ConsumerKey:   c.Credentials.Token,
+		Token:         token,
+		PreviousToken: previousToken,
+		Err:           err,
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/access_token":
+			io.WriteString(w, "oauth_token=token&oauth_token_secret=secret")
+		case "/renew":
+			io.WriteString(w, "oauth_token=renewed&oauth_token_secret=secret")
+		case "/verify":
+			w.Header().Set("WWW-Authenticate", `OAuth oauth_problem="token_revoked"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	var events []AuditEvent
+	c := Client{
+		Credentials:               Credentials{Token: "consumer"},
+		TokenRequestURI:           ts.URL + "/access_token",
+		RenewCredentialRequestURI: ts.URL + "/renew",
+		RevokeTokenURI:            ts.URL + "/revoke",
+		Audit:                     func(e AuditEvent) { events = append(events, e) },
+	}
+	token, _, err := c.RequestToken(nil, &Credentials{Token: "temp"}, "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewed, _, err := c.RenewRequestCredentials(nil, token, "handle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ValidateToken(nil, renewed, ts.URL+"/verify"); err != ErrTokenRevoked {
+		t.Fatalf("ValidateToken returned %v, want ErrTokenRevoked", err)
+	}
+	if err := c.RevokeToken(nil, renewed); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []AuditEvent{
+		{Type: AuditIssued, Token: "token"},
+		{Type: AuditRenewed, Token: "renewed", PreviousToken: "token"},
+		{Type: AuditVerificationFailed, Token: "renewed", Err: ErrTokenRevoked},
+		{Type: AuditRevoked, Token: "renewed"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, e := range events {
+		if e.Time.IsZero() || e.ConsumerKey != "consumer" {
+			t.Errorf("event %d time %v, consumer key %q", i, e.Time, e.ConsumerKey)
+		}
+		w := want[i]
+		if e.Type != w.Type || e.Token != w.Token || e.PreviousToken != w.PreviousToken || e.Err != w.Err {
+			t.Errorf("event %d = %+v, want %+v", i, e, w)
+		}
+	}
+}
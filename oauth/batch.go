@@ -0,0 +1,139 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// BatchRequest is a request sent by Client.DoBatch.
+type BatchRequest struct {
+	// Method is the HTTP method. If this field is the empty string, then GET
+	// is used.
+	Method string
+
+	URL         string
+	Credentials *Credentials
+	Form        url.Values
+}
+
+// BatchOptions specifies the concurrency of Client.DoBatch.
+type BatchOptions struct {
+	// Workers is the maximum number of requests in flight. If this field is
+	// zero, then 4 is used.
+	Workers int
+
+	// PerHost is the maximum number of requests in flight to a single host.
+	// If this field is zero, then there is no limit other than Workers.
+	PerHost int
+}
+
+// BatchError is returned by Client.DoBatch when one or more requests fail.
+type BatchError struct {
+	// Errors has the error for each request or nil if the request succeeded.
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	n := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			n++
+		}
+	}
+	return fmt.Sprintf("oauth: %d of %d requests failed, first error: %v", n, len(e.Errors), first)
+}
+
+// DoBatch sends the requests concurrently. Each request is signed when it is
+// sent. The responses are returned in the order of the requests. The response
+// for a failed request is nil. If any request fails, then DoBatch returns a
+// *BatchError. The application must close the body of each response.
+func (c *Client) DoBatch(ctx context.Context, requests []BatchRequest, options *BatchOptions) ([]*http.Response, error) {
+	workers, perHost := 4, 0
+	if options != nil {
+		if options.Workers > 0 {
+			workers = options.Workers
+		}
+		perHost = options.PerHost
+	}
+
+	responses := make([]*http.Response, len(requests))
+	errs := make([]error, len(requests))
+
+	var mu sync.Mutex
+	hostSems := make(map[string]chan struct{})
+	hostSem := func(urlStr string) chan struct{} {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		sem := hostSems[u.Host]
+		if sem == nil {
+			sem = make(chan struct{}, perHost)
+			hostSems[u.Host] = sem
+		}
+		return sem
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				br := requests[i]
+				method := br.Method
+				if method == "" {
+					method = http.MethodGet
+				}
+				var sem chan struct{}
+				if perHost > 0 {
+					sem = hostSem(br.URL)
+				}
+				if sem != nil {
+					sem <- struct{}{}
+				}
+				responses[i], errs[i] = c.doAPI(ctx, br.URL, &request{op: "api_call", method: method, credentials: br.Credentials, form: br.Form})
+				if sem != nil {
+					<-sem
+				}
+			}
+		}()
+	}
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return responses, &BatchError{Errors: errs}
+		}
+	}
+	return responses, nil
+}
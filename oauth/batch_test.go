@@ -0,0 +1,81 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestDoBatch(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	nonces := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		nonces[r.Header.Get("Authorization")] = true
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		io.WriteString(w, r.FormValue("id"))
+	}))
+	defer ts.Close()
+
+	var requests []BatchRequest
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		requests = append(requests, BatchRequest{URL: ts.URL + "/?id=" + id, Credentials: &Credentials{}})
+	}
+	requests = append(requests, BatchRequest{URL: "http://127.0.0.1:0/", Credentials: &Credentials{}})
+
+	c := Client{}
+	responses, err := c.DoBatch(context.Background(), requests, &BatchOptions{Workers: 4, PerHost: 2})
+	be, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("error %v, want *BatchError", err)
+	}
+	for i, resp := range responses[:6] {
+		if be.Errors[i] != nil {
+			t.Errorf("request %d returned error %v", i, be.Errors[i])
+			continue
+		}
+		p, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if want := requests[i].URL[len(requests[i].URL)-1:]; string(p) != want {
+			t.Errorf("response %d is %q, want %q", i, p, want)
+		}
+	}
+	if responses[6] != nil || be.Errors[6] == nil {
+		t.Errorf("request to closed port did not fail")
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max in flight %d, want <= 2", maxInFlight)
+	}
+	if len(nonces) != 6 {
+		t.Errorf("got %d distinct authorization headers, want 6", len(nonces))
+	}
+}
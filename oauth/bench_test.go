@@ -0,0 +1,153 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.7
+// +build go1.7
+
+package oauth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchParams are the request parameters used by the benchmarks.
+var benchParams = []struct {
+	name string
+	form url.Values
+}{
+	{"small", url.Values{"status": {"hello"}}},
+	{"large", largeBenchForm()},
+	{"unicode", url.Values{"status": {strings.Repeat("ü日\U0001F600 ", 20)}}},
+	{"repeated", url.Values{"id": {"9", "3", "7", "1", "5", "2", "8", "4", "6", "0"}}},
+}
+
+func largeBenchForm() url.Values {
+	form := make(url.Values)
+	for i := 0; i < 32; i++ {
+		form.Set(fmt.Sprintf("param%02d", i), strings.Repeat("v", i))
+	}
+	return form
+}
+
+var (
+	benchURL         = parseURL("https://api.example.com/1.1/statuses/update.json?include_entities=true")
+	benchCredentials = &Credentials{Token: "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb", Secret: "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE"}
+)
+
+func benchClient() *Client {
+	return &Client{
+		Credentials: Credentials{Token: "xvz1evFS4wEEPTGEFPHBog", Secret: "kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw"},
+		Clock:       func() time.Time { return time.Unix(1318622958, 0) },
+		Nonce:       func() string { return "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg" },
+	}
+}
+
+func BenchmarkAuthorizationHeader(b *testing.B) {
+	for _, bp := range benchParams {
+		b.Run(bp.name, func(b *testing.B) {
+			c := benchClient()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.AuthorizationHeaderValue(benchCredentials, "POST", benchURL, bp.form); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBaseString(b *testing.B) {
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     "xvz1evFS4wEEPTGEFPHBog",
+		"oauth_nonce":            "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "1318622958",
+		"oauth_token":            benchCredentials.Token,
+		"oauth_version":          "1.0",
+	}
+	for _, bp := range benchParams {
+		b.Run(bp.name, func(b *testing.B) {
+			c := benchClient()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c.writeBaseString(ioutil.Discard, "POST", benchURL, bp.form, oauthParams)
+			}
+		})
+	}
+}
+
+func BenchmarkSignForm(b *testing.B) {
+	for _, bp := range benchParams {
+		b.Run(bp.name, func(b *testing.B) {
+			c := benchClient()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				form := make(url.Values, len(bp.form)+8)
+				for k, v := range bp.form {
+					form[k] = v
+				}
+				if err := c.SignForm(benchCredentials, "POST", benchURL.String(), form); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHeaderFormat(b *testing.B) {
+	p := map[string]string{
+		"oauth_consumer_key":     "xvz1evFS4wEEPTGEFPHBog",
+		"oauth_nonce":            "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg",
+		"oauth_signature":        "tnnArxj06cWHq44gCs1OSKk/jLY=",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "1318622958",
+		"oauth_token":            benchCredentials.Token,
+		"oauth_version":          "1.0",
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		HeaderStyle{}.format(p)
+	}
+}
+
+// allocBudget is the maximum number of allocations for signing a request
+// with the parameters in benchParams. Update the budget when a change
+// intentionally adds allocations.
+var allocBudget = map[string]float64{
+	"small":    28,
+	"large":    32,
+	"unicode":  28,
+	"repeated": 28,
+}
+
+func TestAllocBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budget in short mode")
+	}
+	c := benchClient()
+	for _, bp := range benchParams {
+		n := testing.AllocsPerRun(100, func() {
+			c.AuthorizationHeaderValue(benchCredentials, "POST", benchURL, bp.form)
+		})
+		t.Logf("%s: %v allocs", bp.name, n)
+		if n > allocBudget[bp.name] {
+			t.Errorf("%s: %v allocations, budget is %v", bp.name, n, allocBudget[bp.name])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitOpenError is returned by CircuitBreaker when requests to a host are
+// blocked.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return "oauth: circuit open for " + e.Host + " until " + e.Until.Format(time.RFC3339)
+}
+
+// Is reports whether target is ErrTemporary.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrTemporary
+}
+
+// CircuitBreaker is a Doer that stops sending requests to a host after
+// repeated failures. A failure is a timeout, a connection error or a server
+// error response. While the circuit for a host is open, Do returns a
+// *CircuitOpenError without sending the request. After the open period, one
+// request is sent to test the host. The circuit is closed if the request
+// succeeds.
+//
+// Use a CircuitBreaker as the HTTP client for the Client methods:
+//
+//	b := &oauth.CircuitBreaker{}
+//	ctx := context.WithValue(ctx, oauth.HTTPClient, b)
+type CircuitBreaker struct {
+	// Doer sends the requests. If this field is nil, then the shared client
+	// created with NewHTTPClient is used.
+	Doer Doer
+
+	// Threshold is the number of consecutive failures that opens the
+	// circuit. If this field is zero, then 5 is used.
+	Threshold int
+
+	// OpenDuration is the time that the circuit stays open. If this field is
+	// zero, then 30 seconds is used.
+	OpenDuration time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+}
+
+func (b *CircuitBreaker) threshold() int {
+	if b.Threshold > 0 {
+		return b.Threshold
+	}
+	return 5
+}
+
+func (b *CircuitBreaker) openDuration() time.Duration {
+	if b.OpenDuration > 0 {
+		return b.OpenDuration
+	}
+	return 30 * time.Second
+}
+
+// Do implements the Doer interface.
+func (b *CircuitBreaker) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	now := time.Now()
+
+	b.mu.Lock()
+	if b.hosts == nil {
+		b.hosts = make(map[string]*circuit)
+	}
+	c := b.hosts[host]
+	if c == nil {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	if now.Before(c.openUntil) {
+		until := c.openUntil
+		b.mu.Unlock()
+		return nil, &CircuitOpenError{Host: host, Until: until}
+	}
+	if c.failures >= b.threshold() {
+		// Block other requests while this request tests the host.
+		c.openUntil = now.Add(b.openDuration())
+	}
+	b.mu.Unlock()
+
+	d := b.Doer
+	if d == nil {
+		d = defaultClient
+	}
+	resp, err := d.Do(req)
+	failed := false
+	if err != nil {
+		failed = IsTemporary(err)
+	} else {
+		failed = resp.StatusCode >= 500
+	}
+
+	b.mu.Lock()
+	if failed {
+		c.failures++
+		if c.failures >= b.threshold() {
+			c.openUntil = time.Now().Add(b.openDuration())
+		}
+	} else {
+		c.failures = 0
+		c.openUntil = time.Time{}
+	}
+	b.mu.Unlock()
+	return resp, err
+}
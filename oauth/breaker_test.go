@@ -0,0 +1,69 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	n := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	b := &CircuitBreaker{Threshold: 2, OpenDuration: 50 * time.Millisecond}
+	c := Client{}
+	get := func() error {
+		resp, err := c.Get(b, &Credentials{}, ts.URL, nil)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("request %d returned error %v", i, err)
+		}
+	}
+	err := get()
+	if oe, ok := err.(*OpError); !ok {
+		t.Fatalf("error %v, want *OpError", err)
+	} else if _, ok := oe.Err.(*CircuitOpenError); !ok {
+		t.Fatalf("error %v, want *CircuitOpenError", oe.Err)
+	}
+	if !IsTemporary(err) {
+		t.Errorf("IsTemporary(%v) = false, want true", err)
+	}
+	if n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	status = http.StatusOK
+	if err := get(); err != nil {
+		t.Fatalf("request after open period returned error %v", err)
+	}
+	if err := get(); err != nil {
+		t.Errorf("request after close returned error %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+)
+
+// signatureProblems is the set of problems reported for a rejected signature.
+// The empty problem is included for providers that do not implement problem
+// reporting.
+var signatureProblems = map[Problem]bool{
+	"":                             true,
+	ProblemSignatureInvalid:        true,
+	ProblemSignatureMethodRejected: true,
+	ProblemTimestampRefused:        true,
+	ProblemNonceUsed:               true,
+	ProblemParameterAbsent:         true,
+	ProblemParameterRejected:       true,
+}
+
+// checkSignatureFailure writes a support bundle to c.SupportBundle if the
+// provider rejected the signature of the request. The body of resp is
+// replaced with a reader that returns the complete body.
+func (c *Client) checkSignatureFailure(r *request, resp *http.Response) {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusBadRequest {
+		return
+	}
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize()))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(p), resp.Body), resp.Body}
+	if err != nil {
+		return
+	}
+	problem := responseProblem(resp.Header, p)
+	if !signatureProblems[problem] || (problem == "" && resp.StatusCode != http.StatusUnauthorized) {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("OAuth support bundle for github.com/garyburd/go-oauth\n\n")
+	buf.Write(r.dump)
+	fmt.Fprintf(&buf, "\nResponse status: %s\n", resp.Status)
+	if problem != "" {
+		fmt.Fprintf(&buf, "Problem: %s\n", problem)
+	}
+	fmt.Fprintf(&buf, "Response header:\n")
+	keys := make([]string, 0, len(resp.Header))
+	for k := range resp.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range resp.Header[k] {
+			if k == "Set-Cookie" {
+				v = redacted
+			}
+			fmt.Fprintf(&buf, "    %s: %s\n", k, v)
+		}
+	}
+	fmt.Fprintf(&buf, "Response body:\n%s\n", p)
+	c.SupportBundle.Write(buf.Bytes())
+}
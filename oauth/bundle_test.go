@@ -0,0 +1,119 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSupportBundle(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=cookiesecret")
+		switch r.URL.Path {
+		case "/signature":
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, "oauth_problem=signature_invalid")
+		case "/expired":
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, "oauth_problem=token_expired")
+		case "/forbidden":
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, "bad request")
+		}
+	}))
+	defer ts.Close()
+
+	var bundle bytes.Buffer
+	c := Client{
+		Credentials:     Credentials{Token: "key", Secret: "clientsecret"},
+		SignatureMethod: PLAINTEXT,
+		SupportBundle:   &bundle,
+	}
+	cred := &Credentials{Token: "token", Secret: "tokensecret"}
+
+	resp, err := c.Get(nil, cred, ts.URL+"/signature", map[string][]string{"q": {"v"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "oauth_problem=signature_invalid" {
+		t.Errorf("body = %q, want the complete response body", body)
+	}
+	s := bundle.String()
+	for _, want := range []string{
+		"Base string: GET&",
+		"q%3Dv",
+		`oauth_signature="REDACTED"`,
+		"Problem: signature_invalid",
+		"Set-Cookie: REDACTED",
+		"Response body:\noauth_problem=signature_invalid\n",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("bundle does not contain %q\n%s", want, s)
+		}
+	}
+	for _, secret := range []string{"clientsecret", "tokensecret", "cookiesecret"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("bundle contains %s\n%s", secret, s)
+		}
+	}
+
+	for _, path := range []string{"/expired", "/forbidden"} {
+		bundle.Reset()
+		resp, err := c.Get(nil, cred, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if bundle.Len() != 0 {
+			t.Errorf("%s wrote bundle\n%s", path, bundle.String())
+		}
+	}
+}
+
+func TestSupportBundle_XAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, "oauth_problem=signature_invalid")
+	}))
+	defer ts.Close()
+
+	var bundle bytes.Buffer
+	c := Client{
+		Credentials:     Credentials{Token: "key", Secret: "clientsecret"},
+		TokenRequestURI: ts.URL + "/access_token?pin=4321",
+		SupportBundle:   &bundle,
+		SensitiveParams: []string{"pin"},
+	}
+	if _, _, err := c.RequestTokenXAuth(nil, &Credentials{}, "user", "hunter2"); err == nil {
+		t.Fatal("RequestTokenXAuth returned nil error")
+	}
+	s := bundle.String()
+	if !strings.Contains(s, "x_auth_password%3DREDACTED") || !strings.Contains(s, "pin%3DREDACTED") {
+		t.Errorf("bundle does not contain redacted parameters\n%s", s)
+	}
+	for _, secret := range []string{"hunter2", "4321", "clientsecret"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("bundle contains %s\n%s", secret, s)
+		}
+	}
+}
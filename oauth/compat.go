@@ -0,0 +1,75 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"strings"
+)
+
+// Compatibility specifies the characters that are encoded in the signature
+// base string for gateways that do not follow section 3.6 of the RFC. Use
+// these settings only when the provider is known to compute signatures this
+// way; the signature does not match on conforming providers. The request is
+// sent with the standard encoding.
+type Compatibility struct {
+	// Unescaped is the set of characters that are not percent encoded.
+	// The characters must be in the set !$'()*+,;:@/?.
+	Unescaped string
+
+	// Escaped is the set of unreserved characters that are percent encoded.
+	// The characters must be in the set -._~.
+	Escaped string
+}
+
+const (
+	compatUnescapable = "!$'()*+,;:@/?"
+	compatEscapable   = "-._~"
+)
+
+// check returns an error if the settings are outside the safe limits. The
+// limits exclude the characters that separate the parts of the base string.
+func (cp *Compatibility) check() error {
+	if cp == nil {
+		return nil
+	}
+	for _, r := range cp.Unescaped {
+		if !strings.ContainsRune(compatUnescapable, r) {
+			return errors.New("oauth: Compatibility.Unescaped contains unsupported character " + string(r))
+		}
+	}
+	for _, r := range cp.Escaped {
+		if !strings.ContainsRune(compatEscapable, r) {
+			return errors.New("oauth: Compatibility.Escaped contains unsupported character " + string(r))
+		}
+	}
+	return nil
+}
+
+// noEscape returns the table of characters that are not encoded in the base
+// string.
+func (cp *Compatibility) noEscape() *[256]bool {
+	if cp == nil || (cp.Unescaped == "" && cp.Escaped == "") {
+		return &noEscape
+	}
+	t := noEscape
+	for i := 0; i < len(cp.Unescaped); i++ {
+		t[cp.Unescaped[i]] = true
+	}
+	for i := 0; i < len(cp.Escaped); i++ {
+		t[cp.Escaped[i]] = false
+	}
+	return &t
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+func TestCompatibility(t *testing.T) {
+	u := parseURL("http://example.com/a*b")
+	form := url.Values{"q": {"*~"}}
+	for _, tt := range []struct {
+		compat *Compatibility
+		want   string
+	}{
+		{nil, "GET&http%3A%2F%2Fexample.com%2Fa%2Ab&q%3D%252A~"},
+		{&Compatibility{}, "GET&http%3A%2F%2Fexample.com%2Fa%2Ab&q%3D%252A~"},
+		{&Compatibility{Unescaped: "*", Escaped: "~"}, "GET&http%3A%2F%2Fexample.com%2Fa*b&q%3D*%257E"},
+	} {
+		var buf bytes.Buffer
+		c := Client{Compatibility: tt.compat}
+		c.writeBaseString(&buf, "GET", u, form, nil)
+		if base := buf.String(); base != tt.want {
+			t.Errorf("Compatibility %+v base string\n    = %q,\n want %q", tt.compat, base, tt.want)
+		}
+		if base := c.BaseString("GET", u, form).String(); base != tt.want {
+			t.Errorf("Compatibility %+v BaseString().String()\n    = %q,\n want %q", tt.compat, base, tt.want)
+		}
+	}
+
+	// The standard table is not changed.
+	if base := string(encode("*~", false)); base != "%2A~" {
+		t.Errorf("encode after Compatibility = %q, want %q", base, "%2A~")
+	}
+}
+
+func TestCompatibility_Check(t *testing.T) {
+	for _, compat := range []*Compatibility{
+		{Unescaped: "&"},
+		{Unescaped: "="},
+		{Unescaped: "%"},
+		{Escaped: "a"},
+	} {
+		c := Client{Compatibility: compat}
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate with %+v returned nil error", compat)
+		}
+		if _, err := c.AuthorizationHeaderValue(&Credentials{}, "GET", parseURL("http://example.com/"), nil); err == nil {
+			t.Errorf("AuthorizationHeaderValue with %+v returned nil error", compat)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffKind identifies a kind of difference between signature base strings.
+type DiffKind int
+
+const (
+	DiffMethod   DiffKind = iota // The methods differ.
+	DiffURI                      // The base string URIs differ.
+	DiffMissing                  // A parameter is missing.
+	DiffExtra                    // A parameter is not expected.
+	DiffValue                    // A parameter has a different value.
+	DiffEncoding                 // A parameter is encoded differently.
+	DiffOrder                    // The parameters are sorted differently.
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffMethod:
+		return "method"
+	case DiffURI:
+		return "uri"
+	case DiffMissing:
+		return "missing"
+	case DiffExtra:
+		return "extra"
+	case DiffValue:
+		return "value"
+	case DiffEncoding:
+		return "encoding"
+	case DiffOrder:
+		return "order"
+	default:
+		return "unknown"
+	}
+}
+
+// Difference is a difference between two signature base strings.
+type Difference struct {
+	Kind DiffKind
+
+	// Key is the decoded parameter key. Key is empty for method and URI
+	// differences.
+	Key string
+
+	// Got and Want are the differing parts of the base strings. The method
+	// and URI are decoded. Parameters are in the form key=value with the
+	// outer level of encoding removed. Got is empty for a missing parameter
+	// and Want is empty for an extra parameter.
+	Got, Want string
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffMethod:
+		return "method " + strconv.Quote(d.Got) + ", want " + strconv.Quote(d.Want)
+	case DiffURI:
+		return "URI " + strconv.Quote(d.Got) + ", want " + strconv.Quote(d.Want)
+	case DiffMissing:
+		return "missing parameter " + strconv.Quote(d.Want)
+	case DiffExtra:
+		return "unexpected parameter " + strconv.Quote(d.Got)
+	case DiffValue:
+		return "parameter " + strconv.Quote(d.Got) + ", want " + strconv.Quote(d.Want)
+	case DiffEncoding:
+		return "parameter encoded as " + strconv.Quote(d.Got) + ", want " + strconv.Quote(d.Want)
+	case DiffOrder:
+		return "parameter " + strconv.Quote(d.Got) + " sorted where " + strconv.Quote(d.Want) + " is expected"
+	default:
+		return "unknown difference"
+	}
+}
+
+// DiffBaseString compares a signature base string computed by the client
+// with the base string expected by the provider. Many providers include the
+// expected base string in the response to a request with an invalid
+// signature. The differences are returned with the method and URI first,
+// followed by the parameter differences sorted by key. If the parameters
+// match but are sorted differently, then the first parameter out of order
+// is returned. DiffBaseString returns nil if the base strings are equal.
+func DiffBaseString(got, want string) ([]Difference, error) {
+	g, err := parseBaseString(got)
+	if err != nil {
+		return nil, err
+	}
+	w, err := parseBaseString(want)
+	if err != nil {
+		return nil, err
+	}
+
+	var d []Difference
+	if g.method != w.method {
+		d = append(d, Difference{Kind: DiffMethod, Got: g.method, Want: w.method})
+	}
+	if g.uri != w.uri {
+		d = append(d, Difference{Kind: DiffURI, Got: g.uri, Want: w.uri})
+	}
+
+	// Match the parameters from the most to the least specific. Exact
+	// matches, passed with kind -1, are not differences.
+	gotUsed := make([]bool, len(g.params))
+	wantUsed := make([]bool, len(w.params))
+	var pd []Difference
+	match := func(same func(gp, wp *baseStringParam) bool, kind DiffKind) {
+		for i := range w.params {
+			if wantUsed[i] {
+				continue
+			}
+			for j := range g.params {
+				if !gotUsed[j] && same(&g.params[j], &w.params[i]) {
+					gotUsed[j], wantUsed[i] = true, true
+					if kind >= 0 {
+						pd = append(pd, Difference{Kind: kind, Key: w.params[i].key, Got: g.params[j].raw, Want: w.params[i].raw})
+					}
+					break
+				}
+			}
+		}
+	}
+	match(func(gp, wp *baseStringParam) bool { return gp.raw == wp.raw }, -1)
+	match(func(gp, wp *baseStringParam) bool { return gp.key == wp.key && gp.value == wp.value }, DiffEncoding)
+	match(func(gp, wp *baseStringParam) bool { return gp.key == wp.key }, DiffValue)
+	for i, p := range w.params {
+		if !wantUsed[i] {
+			pd = append(pd, Difference{Kind: DiffMissing, Key: p.key, Want: p.raw})
+		}
+	}
+	for j, p := range g.params {
+		if !gotUsed[j] {
+			pd = append(pd, Difference{Kind: DiffExtra, Key: p.key, Got: p.raw})
+		}
+	}
+	sort.Stable(byDiffKey(pd))
+	d = append(d, pd...)
+
+	if len(pd) == 0 {
+		for i := range w.params {
+			if g.params[i].raw != w.params[i].raw {
+				d = append(d, Difference{Kind: DiffOrder, Key: g.params[i].key, Got: g.params[i].raw, Want: w.params[i].raw})
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+type byDiffKey []Difference
+
+func (p byDiffKey) Len() int           { return len(p) }
+func (p byDiffKey) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byDiffKey) Less(i, j int) bool { return p[i].Key < p[j].Key }
+
+type baseStringParam struct {
+	raw        string // key=value as encoded in the parameter string
+	key, value string // decoded key and value
+}
+
+type parsedBaseString struct {
+	method string
+	uri    string
+	params []baseStringParam
+}
+
+// parseBaseString splits a signature base string into its parts.
+func parseBaseString(s string) (*parsedBaseString, error) {
+	parts := strings.Split(strings.TrimSpace(s), "&")
+	if len(parts) != 3 {
+		return nil, errors.New("oauth: base string " + strconv.Quote(s) + " does not have three parts")
+	}
+	var b parsedBaseString
+	var err error
+	if b.method, err = unescapeBaseString(parts[0]); err != nil {
+		return nil, err
+	}
+	if b.uri, err = unescapeBaseString(parts[1]); err != nil {
+		return nil, err
+	}
+	params, err := unescapeBaseString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if params == "" {
+		return &b, nil
+	}
+	for _, raw := range strings.Split(params, "&") {
+		k, v := raw, ""
+		if i := strings.IndexByte(raw, '='); i >= 0 {
+			k, v = raw[:i], raw[i+1:]
+		}
+		p := baseStringParam{raw: raw}
+		if p.key, err = unescapeBaseString(k); err != nil {
+			return nil, err
+		}
+		if p.value, err = unescapeBaseString(v); err != nil {
+			return nil, err
+		}
+		b.params = append(b.params, p)
+	}
+	return &b, nil
+}
+
+// unescapeBaseString decodes a percent encoded string. Unlike a query
+// string, a '+' is not decoded as a space.
+func unescapeBaseString(s string) (string, error) {
+	s, err := url.QueryUnescape(strings.Replace(s, "+", "%2B", -1))
+	if err != nil {
+		return "", errors.New("oauth: base string: " + err.Error())
+	}
+	return s, nil
+}
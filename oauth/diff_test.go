@@ -0,0 +1,73 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"reflect"
+	"testing"
+)
+
+var diffBaseStringTests = []struct {
+	got, want string
+	diff      []Difference
+}{
+	{
+		"GET&http%3A%2F%2Fexample.com%2F&a%3D1",
+		"GET&http%3A%2F%2Fexample.com%2F&a%3D1",
+		nil,
+	},
+	{
+		"POST&https%3A%2F%2Fapi.example.com%2Fupdate&a%3D1%26q%3Da%2520b%26t%3D%257E%26z%3D2",
+		"GET&https%3A%2F%2Fapi.example.com%2Fupdate&a%3D1%26b%3D3%26q%3Da%2520b%26t%3D~",
+		[]Difference{
+			{Kind: DiffMethod, Got: "POST", Want: "GET"},
+			{Kind: DiffMissing, Key: "b", Want: "b=3"},
+			{Kind: DiffEncoding, Key: "t", Got: "t=%7E", Want: "t=~"},
+			{Kind: DiffExtra, Key: "z", Got: "z=2"},
+		},
+	},
+	{
+		"GET&http%3A%2F%2Fexample.com%3A80%2F&q%3Da%2520b",
+		"GET&http%3A%2F%2Fexample.com%2F&q%3Da%252Bb",
+		[]Difference{
+			{Kind: DiffURI, Got: "http://example.com:80/", Want: "http://example.com/"},
+			{Kind: DiffValue, Key: "q", Got: "q=a%20b", Want: "q=a%2Bb"},
+		},
+	},
+	{
+		"GET&http%3A%2F%2Fexample.com%2F&a%3D2%26a%3D1",
+		"GET&http%3A%2F%2Fexample.com%2F&a%3D1%26a%3D2",
+		[]Difference{
+			{Kind: DiffOrder, Key: "a", Got: "a=2", Want: "a=1"},
+		},
+	},
+}
+
+func TestDiffBaseString(t *testing.T) {
+	for _, tt := range diffBaseStringTests {
+		diff, err := DiffBaseString(tt.got, tt.want)
+		if err != nil {
+			t.Errorf("DiffBaseString(%q, %q) returned error %v", tt.got, tt.want, err)
+			continue
+		}
+		if !reflect.DeepEqual(diff, tt.diff) {
+			t.Errorf("DiffBaseString(%q, %q)\n    = %v,\n want %v", tt.got, tt.want, diff, tt.diff)
+		}
+	}
+
+	if _, err := DiffBaseString("GET&http%3A%2F%2Fexample.com%2F", "GET&http%3A%2F%2Fexample.com%2F&"); err == nil {
+		t.Error("DiffBaseString with two part base string returned nil error")
+	}
+}
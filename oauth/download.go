@@ -0,0 +1,156 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// DefaultDownloadRetries is the default number of times a download is
+// resumed.
+const DefaultDownloadRetries = 3
+
+// Download is the body of a signed GET for a protected resource such as
+// media or an export. If reading the body fails before the end of the
+// resource, then Download sends a new signed GET with a Range header for the
+// remaining bytes and continues reading from the new response. The transfer
+// is resumed only if the server returned a strong ETag or a Last-Modified
+// header, so that the If-Range header ensures the resource did not change.
+type Download struct {
+	// Size is the size of the resource or -1 if the size is not known.
+	Size int64
+
+	// ContentType is the Content-Type of the resource.
+	ContentType string
+
+	// Header is the header of the first response.
+	Header http.Header
+
+	// Retries is the maximum number of times the transfer is resumed.
+	// Download sets this field to DefaultDownloadRetries. Set this field
+	// before reading to change the limit.
+	Retries int
+
+	ctx         context.Context
+	c           *Client
+	credentials *Credentials
+	urlStr      string
+	form        url.Values
+
+	body      io.ReadCloser
+	offset    int64
+	validator string
+	err       error
+}
+
+// Download issues a signed GET for a resource and returns the response body
+// as a *Download. An error is returned if the response status is not 200.
+// The application must close the download when done reading.
+func (c *Client) Download(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (*Download, error) {
+	resp, err := c.GetContext(ctx, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		p, _ := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize()))
+		drainBody(resp.Body, c.drainLimit())
+		return nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: p,
+			msg: fmt.Sprintf("oauth: download %s returned status %d, %s", urlStr, resp.StatusCode, p)}
+	}
+	d := &Download{
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		Header:      resp.Header,
+		Retries:     DefaultDownloadRetries,
+		ctx:         ctx,
+		c:           c,
+		credentials: credentials,
+		urlStr:      urlStr,
+		form:        form,
+		body:        resp.Body,
+	}
+	if resp.Header.Get("Accept-Ranges") != "none" {
+		if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			d.validator = etag
+		} else {
+			d.validator = resp.Header.Get("Last-Modified")
+		}
+	}
+	return d, nil
+}
+
+// Read implements the io.Reader interface.
+func (d *Download) Read(p []byte) (int, error) {
+	for {
+		if d.err != nil {
+			return 0, d.err
+		}
+		n, err := d.body.Read(p)
+		d.offset += int64(n)
+		if err == io.EOF && d.Size >= 0 && d.offset < d.Size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil || err == io.EOF || d.validator == "" || d.Retries <= 0 {
+			return n, err
+		}
+		d.body.Close()
+		if rerr := d.resume(); rerr != nil {
+			d.err = err
+			return n, err
+		}
+		d.Retries--
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// resume requests the remainder of the resource. The request is signed with
+// a new nonce and timestamp.
+func (d *Download) resume() error {
+	c := *d.c
+	c.Header = make(http.Header)
+	for k, v := range d.c.Header {
+		c.Header[k] = v
+	}
+	c.Header.Set("Range", "bytes="+strconv.FormatInt(d.offset, 10)+"-")
+	c.Header.Set("If-Range", d.validator)
+	resp, err := c.GetContext(d.ctx, d.credentials, d.urlStr, d.form)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent ||
+		!strings.HasPrefix(resp.Header.Get("Content-Range"), "bytes "+strconv.FormatInt(d.offset, 10)+"-") {
+		drainBody(resp.Body, c.drainLimit())
+		return errors.New("oauth: server did not resume download")
+	}
+	d.body = resp.Body
+	return nil
+}
+
+// Close closes the response body.
+func (d *Download) Close() error {
+	d.err = errors.New("oauth: read from closed download")
+	return d.body.Close()
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// failingReader returns an error after n bytes.
+type failingReader struct {
+	r io.ReadCloser
+	n int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.n <= 0 {
+		return 0, errors.New("connection reset")
+	}
+	if len(p) > f.n {
+		p = p[:f.n]
+	}
+	n, err := f.r.Read(p)
+	f.n -= n
+	return n, err
+}
+
+func (f *failingReader) Close() error { return f.r.Close() }
+
+func TestDownload(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "data.bin", time.Time{}, bytes.NewReader(data))
+	}))
+	defer ts.Close()
+
+	for _, tt := range []struct {
+		failures int
+		retries  int
+		wantErr  bool
+	}{
+		{0, DefaultDownloadRetries, false},
+		{2, DefaultDownloadRetries, false},
+		{2, 1, true},
+	} {
+		var ranges, auths []string
+		failures := tt.failures
+		d := doerFunc(func(req *http.Request) (*http.Response, error) {
+			ranges = append(ranges, req.Header.Get("Range"))
+			auths = append(auths, req.Header.Get("Authorization"))
+			resp, err := http.DefaultClient.Do(req)
+			if err == nil && failures > 0 {
+				failures--
+				resp.Body = &failingReader{r: resp.Body, n: 1000}
+			}
+			return resp, err
+		})
+		ctx := context.WithValue(context.Background(), HTTPClient, d)
+
+		var c Client
+		dl, err := c.Download(ctx, &Credentials{}, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dl.Retries = tt.retries
+		got, err := ioutil.ReadAll(dl)
+		dl.Close()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("failures=%d retries=%d: no error", tt.failures, tt.retries)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failures=%d: read returned error %v", tt.failures, err)
+			continue
+		}
+		if !bytes.Equal(got, data) || dl.Size != int64(len(data)) {
+			t.Errorf("failures=%d: got %d bytes, size %d, want %d", tt.failures, len(got), dl.Size, len(data))
+		}
+		if len(ranges) != tt.failures+1 {
+			t.Fatalf("failures=%d: %d requests, want %d", tt.failures, len(ranges), tt.failures+1)
+		}
+		for i := 1; i < len(ranges); i++ {
+			if want := "bytes=" + strconv.Itoa(1000*i) + "-"; ranges[i] != want {
+				t.Errorf("request %d Range %q, want %q", i, ranges[i], want)
+			}
+			if auths[i] == auths[i-1] {
+				t.Errorf("request %d was not signed again", i)
+			}
+		}
+	}
+}
+
+func TestDownload_Status(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	var c Client
+	_, err := c.Download(context.Background(), &Credentials{}, ts.URL, nil)
+	if e, ok := err.(*StatusError); !ok || e.StatusCode != http.StatusNotFound {
+		t.Errorf("Download returned error %v, want StatusError with status 404", err)
+	}
+}
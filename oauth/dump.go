@@ -0,0 +1,92 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+)
+
+const redacted = "REDACTED"
+
+// DumpSignedRequest signs a request and returns a human-readable description
+// of the request, the signature base string and the authorization header. The
+// signature is redacted from the output because the signature is derived from
+// the client and token secrets. The values of x_auth_password and the
+// parameters in SensitiveParams are also redacted. The output is suitable for
+// including in a support request to a provider.
+func (c *Client) DumpSignedRequest(credentials *Credentials, method string, u *url.URL, form url.Values) ([]byte, error) {
+	r := &request{credentials: credentials, method: method, u: u, form: form}
+	p, err := c.oauthParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return c.dump(method, r.u, r.form, p), nil
+}
+
+// dump returns the description of a request signed with the OAuth protocol
+// parameters p. The signature is redacted.
+func (c *Client) dump(method string, u *url.URL, form url.Values, p map[string]string) []byte {
+	params := make(url.Values)
+	for k, vs := range form {
+		params[k] = append(params[k], vs...)
+	}
+	c.redactParams(params)
+	if q := u.Query(); c.redactParams(q) {
+		u2 := *u
+		u2.RawQuery = q.Encode()
+		u = &u2
+	}
+	redactedParams := make(map[string]string, len(p))
+	for k, v := range p {
+		if k != "oauth_signature" {
+			params.Set(k, v)
+			redactedParams[k] = v
+		}
+	}
+	b := c.BaseString(method, u, params)
+
+	redactedParams["oauth_signature"] = redacted
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Method: %s\n", b.Method)
+	fmt.Fprintf(&buf, "URL: %s\n", u)
+	fmt.Fprintf(&buf, "Signature method: %s\n", c.SignatureMethod)
+	fmt.Fprintf(&buf, "Base string URI: %s\n", b.URI)
+	fmt.Fprintf(&buf, "Parameters:\n")
+	for _, kv := range b.Params {
+		fmt.Fprintf(&buf, "    %s=%s\n", kv.Key, kv.Value)
+	}
+	fmt.Fprintf(&buf, "Base string: %s\n", b.String())
+	fmt.Fprintf(&buf, "Authorization: %s\n", c.HeaderStyle.format(redactedParams))
+	return buf.Bytes()
+}
+
+// redactParams redacts the values of sensitive parameters in v and returns
+// true if a value was redacted.
+func (c *Client) redactParams(v url.Values) bool {
+	found := false
+	for k, vs := range v {
+		if k != "x_auth_password" && !containsString(c.SensitiveParams, k) {
+			continue
+		}
+		for i := range vs {
+			vs[i] = redacted
+		}
+		found = true
+	}
+	return found
+}
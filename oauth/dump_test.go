@@ -0,0 +1,57 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpSignedRequest(t *testing.T) {
+	originalTestHook := testHook
+	defer func() {
+		testHook = originalTestHook
+	}()
+
+	for _, ot := range oauthTests {
+		if ot.signatureMethod == RSASHA1 {
+			continue
+		}
+		testHook = func(p map[string]string) {
+			if _, ok := p["oauth_nonce"]; ok {
+				p["oauth_nonce"] = ot.nonce
+			}
+			if _, ok := p["oauth_timestamp"]; ok {
+				p["oauth_timestamp"] = ot.timestamp
+			}
+		}
+		c := Client{Credentials: ot.clientCredentials, SignatureMethod: ot.signatureMethod}
+		dump, err := c.DumpSignedRequest(&ot.credentials, ot.method, ot.url, ot.form)
+		if err != nil {
+			t.Errorf("DumpSignedRequest(&cred, %q, %q, %v) returned error %v", ot.method, ot.url, ot.form, err)
+			continue
+		}
+		s := string(dump)
+		if ot.base != "" && !strings.Contains(s, "Base string: "+ot.base+"\n") {
+			t.Errorf("dump for %s %s does not contain base string %q\n%s", ot.method, ot.url, ot.base, s)
+		}
+		if !strings.Contains(s, `oauth_signature="REDACTED"`) {
+			t.Errorf("dump for %s %s does not contain redacted signature\n%s", ot.method, ot.url, s)
+		}
+		if ot.clientCredentials.Secret != "" && strings.Contains(s, ot.clientCredentials.Secret) {
+			t.Errorf("dump for %s %s contains client secret\n%s", ot.method, ot.url, s)
+		}
+	}
+}
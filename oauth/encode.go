@@ -0,0 +1,102 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrInvalidEncoding is returned by DecodeCredentials and DecodeValue when
+// the value cannot be decrypted or authenticated.
+var ErrInvalidEncoding = errors.New("oauth: invalid encoded credentials")
+
+// EncodeValue encrypts and authenticates p with AES-GCM and returns the
+// result as a compact URL safe string. The key must be 16, 24 or 32 bytes
+// long. The purpose is authenticated but not encrypted. DecodeValue must be
+// called with the same purpose; use a different purpose for each use of the
+// encoded values so that a value issued for one use is rejected by another.
+func EncodeValue(key, p []byte, purpose string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(p)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, p, []byte(purpose))), nil
+}
+
+// DecodeValue returns the value encoded by EncodeValue with the same key and
+// purpose. DecodeValue returns ErrInvalidEncoding if the value was modified
+// or encoded with a different key or purpose.
+func DecodeValue(key []byte, s, purpose string) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	p, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(p) < aead.NonceSize() {
+		return nil, ErrInvalidEncoding
+	}
+	p, err = aead.Open(nil, p[:aead.NonceSize()], p[aead.NonceSize():], []byte(purpose))
+	if err != nil {
+		return nil, ErrInvalidEncoding
+	}
+	return p, nil
+}
+
+// EncodeCredentials encrypts and authenticates credentials with EncodeValue
+// for embedding in a link or cookie. DecodeCredentials must be called with
+// the same key and purpose.
+func EncodeCredentials(key []byte, credentials *Credentials, purpose string) (string, error) {
+	var n [binary.MaxVarintLen64]byte
+	m := binary.PutUvarint(n[:], uint64(len(credentials.Token)))
+	p := make([]byte, 0, m+len(credentials.Token)+len(credentials.Secret))
+	p = append(p, n[:m]...)
+	p = append(p, credentials.Token...)
+	p = append(p, credentials.Secret...)
+	return EncodeValue(key, p, purpose)
+}
+
+// DecodeCredentials returns the credentials encoded by EncodeCredentials with
+// the same key and purpose. DecodeCredentials returns ErrInvalidEncoding if
+// the value was modified or encoded with a different key or purpose.
+func DecodeCredentials(key []byte, s, purpose string) (*Credentials, error) {
+	p, err := DecodeValue(key, s, purpose)
+	if err != nil {
+		return nil, err
+	}
+	n, m := binary.Uvarint(p)
+	if m <= 0 || n > uint64(len(p)-m) {
+		return nil, ErrInvalidEncoding
+	}
+	p = p[m:]
+	return &Credentials{Token: string(p[:n]), Secret: string(p[n:])}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeCredentials(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	for _, cred := range []*Credentials{
+		{Token: "token", Secret: "secret"},
+		{Token: "", Secret: ""},
+		{Token: "tü/+=&", Secret: strings.Repeat("s", 300)},
+	} {
+		s, err := EncodeCredentials(key, cred, "link")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(s, "+/=") || strings.Contains(s, cred.Secret) && cred.Secret != "" {
+			t.Errorf("encoded %q is not URL safe or contains the secret", s)
+		}
+		got, err := DecodeCredentials(key, s, "link")
+		if err != nil {
+			t.Errorf("DecodeCredentials returned error %v", err)
+			continue
+		}
+		if *got != *cred {
+			t.Errorf("DecodeCredentials = %v, want %v", got, cred)
+		}
+	}
+
+	s, _ := EncodeCredentials(key, &Credentials{Token: "token", Secret: "secret"}, "link")
+	// Modify the first character. The last character can hold unused bits.
+	modified := "A" + s[1:]
+	if strings.HasPrefix(s, "A") {
+		modified = "B" + s[1:]
+	}
+	for _, tt := range []struct {
+		key     string
+		s       string
+		purpose string
+	}{
+		{"fedcba9876543210", s, "link"},
+		{string(key), s, "cookie"},
+		{string(key), modified, "link"},
+		{string(key), "!", "link"},
+		{string(key), "", "link"},
+	} {
+		if _, err := DecodeCredentials([]byte(tt.key), tt.s, tt.purpose); err != ErrInvalidEncoding {
+			t.Errorf("DecodeCredentials(%q, %q, %q) returned error %v, want ErrInvalidEncoding", tt.key, tt.s, tt.purpose, err)
+		}
+	}
+
+	if _, err := EncodeCredentials([]byte("short"), &Credentials{}, ""); err == nil {
+		t.Error("EncodeCredentials with short key returned nil error")
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	s, err := EncodeValue(key, []byte("value"), "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := DecodeValue(key, s, "export")
+	if err != nil || string(p) != "value" {
+		t.Errorf("DecodeValue = %q, %v, want value, nil", p, err)
+	}
+	if _, err := DecodeValue(key, s, "link"); err != ErrInvalidEncoding {
+		t.Errorf("DecodeValue with other purpose returned error %v, want ErrInvalidEncoding", err)
+	}
+	if _, err := DecodeCredentials(key, s, "export"); err != ErrInvalidEncoding {
+		t.Errorf("DecodeCredentials of value returned error %v, want ErrInvalidEncoding", err)
+	}
+}
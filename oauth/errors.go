@@ -0,0 +1,218 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Error classes. The errors returned by the Client methods for unexpected
+// responses match these errors using errors.Is in Go 1.13 and later. The
+// IsTemporary, IsAuthError and IsRateLimited functions classify all errors
+// returned by the Client methods, including transport errors.
+var (
+	// ErrTemporary is the class of errors that might not occur if the request
+	// is sent again later.
+	ErrTemporary = errors.New("oauth: temporary failure")
+
+	// ErrAuth is the class of errors caused by invalid, expired or revoked
+	// credentials or an invalid signature.
+	ErrAuth = errors.New("oauth: authentication failed")
+
+	// ErrRateLimited is the class of errors caused by exceeding the
+	// provider's rate limit.
+	ErrRateLimited = errors.New("oauth: rate limited")
+)
+
+// StatusError is returned when a server responds with an unexpected status.
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	msg        string
+}
+
+func (e *StatusError) Error() string {
+	return e.msg
+}
+
+// Is reports whether the error is in the error class target.
+func (e *StatusError) Is(target error) bool {
+	return target != nil && statusClass(e.StatusCode, e.Header, e.Body) == target
+}
+
+// Is reports whether the error is in the error class target.
+func (e RequestCredentialsError) Is(target error) bool {
+	return target != nil && statusClass(e.StatusCode, e.Header, e.Body) == target
+}
+
+// statusClass returns the error class for a response or nil if the response
+// is not in a class.
+func statusClass(status int, header http.Header, body []byte) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrAuth
+	case http.StatusBadRequest, http.StatusForbidden:
+		if responseProblem(header, body) != "" {
+			return ErrAuth
+		}
+	case http.StatusTooManyRequests, 420: // 420 is used by older Twitter APIs.
+		return ErrRateLimited
+	case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrTemporary
+	}
+	return nil
+}
+
+// errorClass returns the error class for err or nil if the error is not in a
+// class.
+func errorClass(err error) error {
+	for err != nil {
+		switch err {
+		case ErrTemporary, ErrAuth, ErrRateLimited:
+			return err
+		case ErrTokenInvalid, ErrTokenExpired, ErrTokenRevoked:
+			return ErrAuth
+		case ErrPoolExhausted:
+			return ErrRateLimited
+		}
+		if IsNotSent(err) {
+			return ErrTemporary
+		}
+		switch e := err.(type) {
+		case *StatusError:
+			return statusClass(e.StatusCode, e.Header, e.Body)
+		case RequestCredentialsError:
+			return statusClass(e.StatusCode, e.Header, e.Body)
+		case *CircuitOpenError:
+			return ErrTemporary
+		case *url.Error:
+			err = e.Err
+			continue
+		case net.Error:
+			if e.Timeout() {
+				return ErrTemporary
+			}
+		}
+		u, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+// IsTemporary returns true if err is a timeout, a connection failure or a
+// server error response that might not occur if the request is sent again
+// later.
+func IsTemporary(err error) bool {
+	return errorClass(err) == ErrTemporary
+}
+
+// IsAuthError returns true if err shows that the provider rejected the
+// credentials or the signature of a request.
+func IsAuthError(err error) bool {
+	return errorClass(err) == ErrAuth
+}
+
+// IsRateLimited returns true if err shows that the provider rejected a
+// request because the application or user exceeded a rate limit.
+func IsRateLimited(err error) bool {
+	return errorClass(err) == ErrRateLimited
+}
+
+// IsCallbackRejected returns true if err shows that the provider rejected the
+// callback URL in a temporary credentials request. Providers that require a
+// registered callback URL reject URLs that do not match the registered URL
+// exactly. The provider reports the problem with the parameter_rejected or
+// parameter_absent problem for oauth_callback or, for providers that do not
+// implement problem reporting, with an error response that mentions the
+// callback.
+func IsCallbackRejected(err error) bool {
+	for err != nil {
+		if e, ok := err.(RequestCredentialsError); ok {
+			return callbackRejected(e.StatusCode, e.Header, e.Body)
+		}
+		u, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return false
+}
+
+func callbackRejected(status int, header http.Header, body []byte) bool {
+	switch responseProblem(header, body) {
+	case ProblemParameterRejected, ProblemParameterAbsent:
+		return strings.Contains(header.Get("WWW-Authenticate"), "oauth_callback") ||
+			strings.Contains(string(body), "oauth_callback")
+	case "":
+		return status >= 400 && status < 500 && strings.Contains(strings.ToLower(string(body)), "callback")
+	}
+	return false
+}
+
+// OpError is the error returned by the Client methods when a request cannot
+// be signed or sent, a hook or RenewCredentials fails, or the response body
+// cannot be read. A body read error for a credentials request is wrapped in a
+// RequestCredentialsError.
+type OpError struct {
+	// Op is the operation: request_token, access_token, renew_token,
+	// validate_token, revoke_token or api_call.
+	Op string
+
+	// Host is the host of the endpoint.
+	Host string
+
+	// Err is the underlying error. The URL in a *url.Error does not include
+	// the query string.
+	Err error
+}
+
+func (e *OpError) Error() string {
+	return "oauth: " + e.Op + " " + e.Host + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// newOpError returns an error for operation op on URL u. The query string is
+// removed from the URL in a *url.Error because the query can contain
+// sensitive data.
+func newOpError(op string, u *url.URL, err error) error {
+	if op == "" {
+		op = "request"
+	}
+	if ue, ok := err.(*url.Error); ok {
+		u2 := *u
+		u2.RawQuery = ""
+		u2.User = nil
+		err = &url.Error{Op: ue.Op, URL: u2.String(), Err: ue.Err}
+	}
+	return &OpError{Op: op, Host: u.Host, Err: err}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var errorClassTests = []struct {
+	err  error
+	want error
+}{
+	{nil, nil},
+	{errors.New("other"), nil},
+	{ErrTokenExpired, ErrAuth},
+	{RequestCredentialsError{StatusCode: http.StatusUnauthorized}, ErrAuth},
+	{RequestCredentialsError{StatusCode: http.StatusBadRequest, Body: []byte("oauth_problem=nonce_used")}, ErrAuth},
+	{RequestCredentialsError{StatusCode: http.StatusBadRequest}, nil},
+	{&StatusError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited},
+	{&StatusError{StatusCode: http.StatusServiceUnavailable}, ErrTemporary},
+	{&url.Error{Op: "Get", URL: "http://example.com", Err: timeoutError{}}, ErrTemporary},
+	{&url.Error{Op: "Get", URL: "http://example.com", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}, ErrTemporary},
+}
+
+func TestErrorClass(t *testing.T) {
+	for _, tt := range errorClassTests {
+		if got := errorClass(tt.err); got != tt.want {
+			t.Errorf("errorClass(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+	if !IsRateLimited(&StatusError{StatusCode: 420}) {
+		t.Error("IsRateLimited(420) = false, want true")
+	}
+	if !IsAuthError(ErrTokenRevoked) || IsTemporary(ErrTokenRevoked) {
+		t.Error("ErrTokenRevoked not classified as auth error")
+	}
+}
+
+var callbackRejectedTests = []struct {
+	err  error
+	want bool
+}{
+	{nil, false},
+	{errors.New("callback"), false},
+	{RequestCredentialsError{StatusCode: http.StatusBadRequest, Body: []byte("oauth_problem=parameter_rejected&oauth_parameters_rejected=oauth_callback")}, true},
+	{RequestCredentialsError{StatusCode: http.StatusBadRequest, Body: []byte("oauth_problem=parameter_rejected&oauth_parameters_rejected=oauth_nonce")}, false},
+	{RequestCredentialsError{StatusCode: http.StatusUnauthorized, Header: http.Header{"Www-Authenticate": {`OAuth oauth_problem="parameter_absent", oauth_parameters_absent="oauth_callback"`}}}, true},
+	{RequestCredentialsError{StatusCode: http.StatusForbidden, Body: []byte("Callback URL not approved for this client application")}, true},
+	{RequestCredentialsError{StatusCode: http.StatusUnauthorized, Body: []byte("oauth_problem=signature_invalid")}, false},
+	{RequestCredentialsError{StatusCode: http.StatusInternalServerError, Body: []byte("callback failed")}, false},
+	{&OpError{Op: "request_token", Err: RequestCredentialsError{StatusCode: http.StatusForbidden, Body: []byte("invalid callback")}}, true},
+}
+
+func TestIsCallbackRejected(t *testing.T) {
+	for _, tt := range callbackRejectedTests {
+		if got := IsCallbackRejected(tt.err); got != tt.want {
+			t.Errorf("IsCallbackRejected(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestOpError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	urlStr := ts.URL
+	ts.Close()
+
+	c := Client{}
+	_, err := c.Get(http.DefaultClient, &Credentials{}, urlStr+"/path?secret=xyz", url.Values{"q": {"abc"}})
+	oe, ok := err.(*OpError)
+	if !ok {
+		t.Fatalf("error %v is not an *OpError", err)
+	}
+	if oe.Op != "api_call" || oe.Host != strings.TrimPrefix(urlStr, "http://") {
+		t.Errorf("Op, Host = %q, %q, want api_call, %s", oe.Op, oe.Host, urlStr)
+	}
+	if s := err.Error(); strings.Contains(s, "xyz") || strings.Contains(s, "abc") {
+		t.Errorf("error %q contains query string", s)
+	}
+	if _, ok := oe.Unwrap().(*url.Error); !ok {
+		t.Errorf("Unwrap() = %T, want *url.Error", oe.Unwrap())
+	}
+	if !IsNotSent(err) || !IsTemporary(err) {
+		t.Errorf("IsNotSent(%v) or IsTemporary(%v) = false, want true", err, err)
+	}
+
+	c.TemporaryCredentialRequestURI = urlStr
+	_, err = c.RequestTemporaryCredentials(http.DefaultClient, "", nil)
+	if oe, ok := err.(*OpError); !ok || oe.Op != "request_token" {
+		t.Errorf("error %v, want *OpError with op request_token", err)
+	}
+	// Signing, hook and renewal errors also report the operation and host.
+	errSign := errors.New("sign")
+	c = Client{SignatureMethod: RSASHA1}
+	_, err = c.Get(http.DefaultClient, &Credentials{}, "http://example.com/", nil)
+	if oe, ok := err.(*OpError); !ok || oe.Op != "api_call" || oe.Host != "example.com" {
+		t.Errorf("signing error %v, want *OpError with op api_call", err)
+	}
+	c = Client{ResponseHook: func(resp *http.Response) error { return errSign }}
+	d := doerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	})
+	_, err = c.Get(d, &Credentials{}, "http://example.com/", nil)
+	if oe, ok := err.(*OpError); !ok || oe.Err != errSign {
+		t.Errorf("hook error %v, want *OpError with %v", err, errSign)
+	}
+}
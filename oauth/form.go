@@ -0,0 +1,61 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// HiddenFormFields returns the parameters for hidden input elements of a
+// browser form POST to urlStr. The parameters include the form parameters and
+// the signed OAuth protocol parameters in sorted order. Parameters in the
+// urlStr query string are included in the signature. The form element must
+// use the POST method and urlStr as the action. Render the parameters with
+// html/template:
+//
+//	<form method="POST" action="{{.URL}}">
+//	{{range .Fields}}<input type="hidden" name="{{.Key}}" value="{{.Value}}">{{end}}
+//	<input type="submit"></form>
+//
+// The signature expires with the timestamp. Generate the fields for each page
+// view. An error is returned for the PLAINTEXT signature method because the
+// signature contains the client and token secrets.
+func (c *Client) HiddenFormFields(credentials *Credentials, urlStr string, form url.Values) ([]Param, error) {
+	if c.SignatureMethod == PLAINTEXT {
+		return nil, errors.New("oauth: PLAINTEXT signature cannot be sent in a browser form")
+	}
+	signed := make(url.Values, len(form))
+	for k, v := range form {
+		signed[k] = v
+	}
+	if err := c.SignForm(credentials, http.MethodPost, urlStr, signed); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(signed))
+	for k := range signed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var fields []Param
+	for _, k := range keys {
+		for _, v := range signed[k] {
+			fields = append(fields, Param{Key: k, Value: v})
+		}
+	}
+	return fields, nil
+}
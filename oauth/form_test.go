@@ -0,0 +1,57 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHiddenFormFields(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{"key", "secret"},
+		Clock:       func() time.Time { return time.Unix(1, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	form := url.Values{"item": {`"a" & <b>`}}
+	fields, err := c.HiddenFormFields(nil, "https://example.com/pay", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := url.Values{"item": {`"a" & <b>`}}
+	if err := c.SignForm(nil, "POST", "https://example.com/pay", signed); err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != len(signed) {
+		t.Errorf("fields %v, want %v", fields, signed)
+	}
+	for i, f := range fields {
+		if i > 0 && fields[i-1].Key > f.Key {
+			t.Errorf("fields %v not sorted", fields)
+		}
+		if signed.Get(f.Key) != f.Value {
+			t.Errorf("field %s = %q, want %q", f.Key, f.Value, signed.Get(f.Key))
+		}
+	}
+	if len(form) != 1 {
+		t.Errorf("HiddenFormFields modified form: %v", form)
+	}
+
+	c.SignatureMethod = PLAINTEXT
+	if _, err := c.HiddenFormFields(nil, "https://example.com/pay", form); err == nil {
+		t.Error("HiddenFormFields with PLAINTEXT returned nil error")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// AuthScheme specifies the Authorization header sent by the Get, Head, Post,
+// Put and Delete methods for requests to a host.
+type AuthScheme int
+
+const (
+	// AuthOAuth signs the request with OAuth 1.0a.
+	AuthOAuth AuthScheme = iota
+
+	// AuthBearer sends the token of the request credentials as an OAuth 2.0
+	// bearer token. The request credentials must not be nil. The request URL
+	// must use the https scheme.
+	AuthBearer
+
+	// AuthBasic sends the client credentials token and secret as the HTTP
+	// Basic authentication user and password. The request URL must use the
+	// https scheme.
+	AuthBasic
+)
+
+// hostAuthorization returns the Authorization header for a request to a host
+// with a scheme other than AuthOAuth in HostAuthorization. The boolean result
+// is false if the request is signed with OAuth 1.0a.
+func (c *Client) hostAuthorization(r *request) (string, bool, error) {
+	if r.op != "api_call" || len(c.HostAuthorization) == 0 {
+		return "", false, nil
+	}
+	scheme := c.HostAuthorization[strings.ToLower(r.u.Host)]
+	if scheme != AuthOAuth && r.u.Scheme != "https" {
+		return "", true, errors.New("oauth: " + r.u.Scheme + " URL " + r.u.Host + " does not use TLS; bearer and basic authorization require https")
+	}
+	switch scheme {
+	case AuthBearer:
+		if r.credentials == nil {
+			return "", true, errors.New("oauth: bearer authorization for " + r.u.Host + " requires credentials")
+		}
+		return "Bearer " + r.credentials.Token, true, nil
+	case AuthBasic:
+		userPass := c.Credentials.Token + ":" + c.Credentials.Secret
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(userPass)), true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestHostAuthorization(t *testing.T) {
+	var got string
+	d := doerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+	})
+	ctx := context.WithValue(context.Background(), HTTPClient, d)
+	c := Client{
+		Credentials: Credentials{Token: "user", Secret: "pass"},
+		HostAuthorization: map[string]AuthScheme{
+			"api.example.com":      AuthBearer,
+			"upload.example.com":   AuthBasic,
+			"legacy.example.com":   AuthOAuth,
+			"api.example.com:8443": AuthOAuth,
+		},
+	}
+	cred := &Credentials{Token: "access", Secret: "secret"}
+	for _, tt := range []struct {
+		url  string
+		want string
+	}{
+		{"https://api.example.com/2/users", "Bearer access"},
+		{"https://API.Example.com/2/users", "Bearer access"},
+		{"https://upload.example.com/media", "Basic dXNlcjpwYXNz"},
+		{"https://legacy.example.com/1.1/users", "OAuth "},
+		{"https://api.example.com:8443/1.1/users", "OAuth "},
+		{"https://other.example.com/", "OAuth "},
+	} {
+		resp, err := c.GetContext(ctx, cred, tt.url, nil)
+		if err != nil {
+			t.Errorf("GET %s returned error %v", tt.url, err)
+			continue
+		}
+		resp.Body.Close()
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("GET %s sent Authorization %q, want prefix %q", tt.url, got, tt.want)
+		}
+	}
+
+	if _, err := c.GetContext(ctx, nil, "https://api.example.com/2/users", nil); err == nil {
+		t.Error("GET with bearer authorization and nil credentials returned nil error")
+	}
+	for _, urlStr := range []string{"http://api.example.com/2/users", "http://upload.example.com/media"} {
+		got = ""
+		if _, err := c.GetContext(ctx, cred, urlStr, nil); err == nil || got != "" {
+			t.Errorf("GET %s returned error %v and sent Authorization %q, want error", urlStr, err, got)
+		}
+	}
+
+	// Credential requests are always signed.
+	c.TemporaryCredentialRequestURI = "https://api.example.com/request_token"
+	c.RequestTemporaryCredentialsContext(ctx, "oob", nil)
+	if !strings.HasPrefix(got, "OAuth ") {
+		t.Errorf("temporary credentials request sent Authorization %q", got)
+	}
+}
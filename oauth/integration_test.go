@@ -0,0 +1,111 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+// integrationProviders are sandbox applications at real providers. The tests
+// for a provider run when the environment variables
+// OAUTH_TEST_<NAME>_KEY and OAUTH_TEST_<NAME>_SECRET are set to the consumer
+// key and secret. The API call runs when OAUTH_TEST_<NAME>_TOKEN and
+// OAUTH_TEST_<NAME>_TOKEN_SECRET are also set to token credentials for the
+// application.
+var integrationProviders = []struct {
+	name   string
+	client Client
+	apiURL string
+	form   url.Values
+}{
+	{
+		name: "TWITTER",
+		client: Client{
+			TemporaryCredentialRequestURI: "https://api.twitter.com/oauth/request_token",
+			ResourceOwnerAuthorizationURI: "https://api.twitter.com/oauth/authorize",
+			TokenRequestURI:               "https://api.twitter.com/oauth/access_token",
+		},
+		apiURL: "https://api.twitter.com/1.1/account/verify_credentials.json",
+		form:   url.Values{"skip_status": {"true"}, "include_entities": {"false"}},
+	},
+	{
+		name: "TRELLO",
+		client: Client{
+			TemporaryCredentialRequestURI: "https://trello.com/1/OAuthGetRequestToken",
+			ResourceOwnerAuthorizationURI: "https://trello.com/1/OAuthAuthorizeToken",
+			TokenRequestURI:               "https://trello.com/1/OAuthGetAccessToken",
+		},
+		apiURL: "https://api.trello.com/1/members/me",
+		// The comma is a reserved character.
+		form: url.Values{"fields": {"username,fullName"}},
+	},
+	{
+		name: "DISCOGS",
+		client: Client{
+			TemporaryCredentialRequestURI: "https://api.discogs.com/oauth/request_token",
+			ResourceOwnerAuthorizationURI: "https://www.discogs.com/oauth/authorize",
+			TokenRequestURI:               "https://api.discogs.com/oauth/access_token",
+			TemporaryCredentialsMethod:    http.MethodGet,
+			SignatureMethod:               PLAINTEXT,
+			Header:                        http.Header{"User-Agent": {"go-oauth-integration-test"}},
+		},
+		apiURL: "https://api.discogs.com/oauth/identity",
+	},
+}
+
+func TestIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+	for _, p := range integrationProviders {
+		key := os.Getenv("OAUTH_TEST_" + p.name + "_KEY")
+		secret := os.Getenv("OAUTH_TEST_" + p.name + "_SECRET")
+		if key == "" || secret == "" {
+			t.Logf("%s: skipped, OAUTH_TEST_%s_KEY and OAUTH_TEST_%s_SECRET not set", p.name, p.name, p.name)
+			continue
+		}
+		c := p.client
+		c.Credentials = Credentials{Token: key, Secret: secret}
+
+		tempCred, err := c.RequestTemporaryCredentials(nil, "oob", nil)
+		if err != nil {
+			t.Errorf("%s: RequestTemporaryCredentials returned error %v", p.name, err)
+			continue
+		}
+		if tempCred.Token == "" {
+			t.Errorf("%s: RequestTemporaryCredentials returned empty token", p.name)
+		}
+
+		token := os.Getenv("OAUTH_TEST_" + p.name + "_TOKEN")
+		tokenSecret := os.Getenv("OAUTH_TEST_" + p.name + "_TOKEN_SECRET")
+		if token == "" || tokenSecret == "" {
+			continue
+		}
+		resp, err := c.Get(nil, &Credentials{Token: token, Secret: tokenSecret}, p.apiURL, p.form)
+		if err != nil {
+			t.Errorf("%s: Get returned error %v", p.name, err)
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: Get returned status %d, %s", p.name, resp.StatusCode, body)
+		}
+	}
+}
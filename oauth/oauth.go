@@ -58,6 +58,10 @@
 // supplied net/http Client. These methods are easy to use, but not as flexible
 // as constructing a request using one of the low-level methods.
 //
+// A form or query string can contain more than one value for a key. All
+// values are included in the signature. As specified in section 3.4.1.3.2 of
+// the RFC, parameters are sorted by encoded key and then by encoded value.
+//
 // Context With HTTP Client
 //
 // A context-enabled method can include a custom HTTP client in the
@@ -67,6 +71,13 @@
 //     ctx := context.WithValue(context.Background(), oauth.HTTPClient, hc)
 //     c := oauth.Client{ /* Any settings */ }
 //     resp, err := c.GetContext(ctx, &oauth.Credentials{}, rawurl, nil)
+//
+// If the context does not include an HTTP client or the client argument to a
+// method is nil, then a shared client created with NewHTTPClient is used.
+//
+// The WithCredentials function adds token credentials to a context. The
+// GetContext, HeadContext, PostContext, PutContext and DeleteContext methods
+// use these credentials when the credentials argument is nil.
 package oauth // import "github.com/garyburd/go-oauth/oauth"
 
 import (
@@ -76,12 +87,16 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"sort"
@@ -107,7 +122,18 @@ var noEscape = [256]bool{
 // encode encodes string per section 3.6 of the RFC. If double is true, then
 // the encoding is applied twice.
 func encode(s string, double bool) []byte {
-	// Compute size of result.
+	return encodeTable(s, double, &noEscape)
+}
+
+// encodeTable is like encode, but does not encode the characters set in
+// table t.
+func encodeTable(s string, double bool, t *[256]bool) []byte {
+	return appendEncodeTable(make([]byte, 0, encodedLen(s, double)), s, double, t)
+}
+
+// encodedLen returns the length of the encoding of s with the standard
+// table.
+func encodedLen(s string, double bool) int {
 	m := 3
 	if double {
 		m = 5
@@ -120,28 +146,26 @@ func encode(s string, double bool) []byte {
 			n += m
 		}
 	}
+	return n
+}
 
-	p := make([]byte, n)
+// appendEncode appends the encoding of s to p and returns the extended
+// buffer.
+func appendEncode(p []byte, s string, double bool) []byte {
+	return appendEncodeTable(p, s, double, &noEscape)
+}
 
-	// Encode it.
-	j := 0
+// appendEncodeTable is like appendEncode, but does not encode the characters
+// set in table t.
+func appendEncodeTable(p []byte, s string, double bool, t *[256]bool) []byte {
 	for i := 0; i < len(s); i++ {
 		b := s[i]
-		if noEscape[b] {
-			p[j] = b
-			j++
+		if t[b] {
+			p = append(p, b)
 		} else if double {
-			p[j] = '%'
-			p[j+1] = '2'
-			p[j+2] = '5'
-			p[j+3] = "0123456789ABCDEF"[b>>4]
-			p[j+4] = "0123456789ABCDEF"[b&15]
-			j += 5
+			p = append(p, '%', '2', '5', "0123456789ABCDEF"[b>>4], "0123456789ABCDEF"[b&15])
 		} else {
-			p[j] = '%'
-			p[j+1] = "0123456789ABCDEF"[b>>4]
-			p[j+2] = "0123456789ABCDEF"[b&15]
-			j += 3
+			p = append(p, '%', "0123456789ABCDEF"[b>>4], "0123456789ABCDEF"[b&15])
 		}
 	}
 	return p
@@ -149,6 +173,11 @@ func encode(s string, double bool) []byte {
 
 type keyValue struct{ key, value []byte }
 
+var (
+	encodedAmp   = []byte("%26")
+	encodedEqual = []byte("%3D")
+)
+
 type byKeyValue []keyValue
 
 func (p byKeyValue) Len() int      { return len(p) }
@@ -161,62 +190,175 @@ func (p byKeyValue) Less(i, j int) bool {
 	return sgn < 0
 }
 
-func (p byKeyValue) appendValues(values url.Values) byKeyValue {
+// smallParams is the number of parameters sorted without allocation. The
+// common request has at most 8 OAuth parameters and 16 request parameters.
+const smallParams = 24
+
+// sortParams sorts p. Small slices are sorted in place with an insertion
+// sort so that a slice backed by an array on the caller's stack does not
+// escape to the heap.
+func (p byKeyValue) sortParams() {
+	if len(p) > smallParams {
+		q := append(byKeyValue(nil), p...)
+		sort.Sort(q)
+		copy(p, q)
+		return
+	}
+	for i := 1; i < len(p); i++ {
+		for j := i; j > 0 && p.Less(j, j-1); j-- {
+			p.Swap(j, j-1)
+		}
+	}
+}
+
+// paramEncoder encodes parameters to a single buffer.
+type paramEncoder struct {
+	buf      []byte
+	double   bool
+	noEscape *[256]bool
+}
+
+func (e *paramEncoder) encode(s string) []byte {
+	i := len(e.buf)
+	e.buf = appendEncodeTable(e.buf, s, e.double, e.noEscape)
+	return e.buf[i:len(e.buf):len(e.buf)]
+}
+
+func (e *paramEncoder) appendValues(p byKeyValue, values url.Values, exclude []string, normalize func(string) string) byKeyValue {
 	for k, vs := range values {
-		k := encode(k, true)
+		if containsString(exclude, k) {
+			continue
+		}
+		if normalize != nil {
+			k = normalize(k)
+		}
+		ek := e.encode(k)
 		for _, v := range vs {
-			v := encode(v, true)
-			p = append(p, keyValue{k, v})
+			if normalize != nil {
+				v = normalize(v)
+			}
+			p = append(p, keyValue{ek, e.encode(v)})
 		}
 	}
 	return p
 }
 
-// writeBaseString writes method, url, and params to w using the OAuth signature
-// base string computation described in section 3.4.1 of the RFC.
-func writeBaseString(w io.Writer, method string, u *url.URL, form url.Values, oauthParams map[string]string) {
-	// Method
-	w.Write(encode(strings.ToUpper(method), false))
-	w.Write([]byte{'&'})
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
 
-	// URL
+// baseStringURI returns the base string URI described in section 3.4.1.2 of
+// the RFC.
+func (c *Client) baseStringURI(u *url.URL) string {
 	scheme := strings.ToLower(u.Scheme)
 	host := strings.ToLower(u.Host)
 
-	uNoQuery := *u
-	uNoQuery.RawQuery = ""
-	path := uNoQuery.RequestURI()
+	var path string
+	if c.UnescapedPath && u.Opaque == "" {
+		path = u.Path
+		if path == "" {
+			path = "/"
+		}
+	} else {
+		// RequestURI returns the escaped path or the opaque value.
+		uNoQuery := *u
+		uNoQuery.RawQuery = ""
+		path = uNoQuery.RequestURI()
+	}
 
-	switch {
-	case scheme == "http" && strings.HasSuffix(host, ":80"):
-		host = host[:len(host)-len(":80")]
-	case scheme == "https" && strings.HasSuffix(host, ":443"):
-		host = host[:len(host)-len(":443")]
+	if !c.RetainDefaultPort {
+		if port := c.defaultPort(scheme); port != "" && strings.HasSuffix(host, ":"+port) {
+			host = host[:len(host)-len(port)-1]
+		}
 	}
 
-	w.Write(encode(scheme, false))
-	w.Write(encode("://", false))
-	w.Write(encode(host, false))
-	w.Write(encode(path, false))
-	w.Write([]byte{'&'})
+	return scheme + "://" + host + path
+}
+
+// defaultPort returns the default port for the lowercase scheme or "" if the
+// scheme does not have a known default port.
+func (c *Client) defaultPort(scheme string) string {
+	for s, port := range c.DefaultPorts {
+		if strings.ToLower(s) == scheme {
+			return port
+		}
+	}
+	switch scheme {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
+
+// sortedParams returns the sorted and encoded request parameters as described
+// in section 3.4.1.3.2 of the RFC. If double is true, then the parameter keys
+// and values are double encoded in a single step. This is safe because double
+// encoding does not change the sort order.
+func (c *Client) sortedParams(u *url.URL, form url.Values, oauthParams map[string]string, double bool) byKeyValue {
+	return c.appendSortedParams(nil, u, form, oauthParams, double)
+}
 
-	// Create sorted slice of encoded parameters. Parameter keys and values are
-	// double encoded in a single step. This is safe because double encoding
-	// does not change the sort order.
+// appendSortedParams appends the sorted and encoded request parameters to p
+// and returns the extended slice. The encodings share one buffer.
+func (c *Client) appendSortedParams(p byKeyValue, u *url.URL, form url.Values, oauthParams map[string]string, double bool) byKeyValue {
 	queryParams := u.Query()
-	p := make(byKeyValue, 0, len(form)+len(queryParams)+len(oauthParams))
-	p = p.appendValues(form)
-	p = p.appendValues(queryParams)
+	e := paramEncoder{double: double, noEscape: c.Compatibility.noEscape()}
+	// Size the buffer for the encodings. The size is a lower bound when
+	// NormalizeParam expands the parameters and an upper bound when
+	// Compatibility leaves characters unescaped; append grows the buffer.
+	n := 0
+	for _, params := range [...]url.Values{form, queryParams} {
+		for k, vs := range params {
+			for _, v := range vs {
+				n += encodedLen(k, double) + encodedLen(v, double)
+			}
+		}
+	}
 	for k, v := range oauthParams {
-		p = append(p, keyValue{encode(k, true), encode(v, true)})
+		n += encodedLen(k, double) + encodedLen(v, double)
+	}
+	e.buf = make([]byte, 0, n)
+	p = e.appendValues(p, form, c.ExcludeParams, c.NormalizeParam)
+	p = e.appendValues(p, queryParams, c.ExcludeParams, c.NormalizeParam)
+	for k, v := range oauthParams {
+		if c.NormalizeParam != nil {
+			k, v = c.NormalizeParam(k), c.NormalizeParam(v)
+		}
+		p = append(p, keyValue{e.encode(k), e.encode(v)})
+	}
+	// Parameters with the same key are sorted by value.
+	p.sortParams()
+	return p
+}
+
+// writeBaseString writes method, url, and params to w using the OAuth signature
+// base string computation described in section 3.4.1 of the RFC.
+func (c *Client) writeBaseString(w io.Writer, method string, u *url.URL, form url.Values, oauthParams map[string]string) {
+	if c.LowercaseEscapes {
+		w = lowercaseEscapeWriter{w}
 	}
-	sort.Sort(p)
+
+	t := c.Compatibility.noEscape()
+
+	// Method
+	w.Write(encodeTable(strings.ToUpper(method), false, t))
+	w.Write([]byte{'&'})
+
+	// URL
+	w.Write(c.encodedBaseStringURI(u, t))
+	w.Write([]byte{'&'})
 
 	// Write the parameters.
-	encodedAmp := encode("&", false)
-	encodedEqual := encode("=", false)
+	var params [smallParams]keyValue
 	sep := false
-	for _, kv := range p {
+	for _, kv := range c.appendSortedParams(params[:0], u, form, oauthParams, true) {
 		if sep {
 			w.Write(encodedAmp)
 		} else {
@@ -228,6 +370,106 @@ func writeBaseString(w io.Writer, method string, u *url.URL, form url.Values, oa
 	}
 }
 
+// lowercaseEscapeWriter converts the hex digits in percent encodings to
+// lowercase. Each write must contain complete encodings.
+type lowercaseEscapeWriter struct {
+	w io.Writer
+}
+
+func (lw lowercaseEscapeWriter) Write(p []byte) (int, error) {
+	q := make([]byte, len(p))
+	copy(q, p)
+	for i := 0; i < len(q); i++ {
+		if q[i] != '%' {
+			continue
+		}
+		n := 2
+		if i+2 < len(q) && q[i+1] == '2' && q[i+2] == '5' {
+			// The digits following an encoded '%' are also hex digits.
+			n = 4
+		}
+		for j := i + 1; j <= i+n && j < len(q); j++ {
+			if 'A' <= q[j] && q[j] <= 'F' {
+				q[j] += 'a' - 'A'
+			}
+		}
+		i += n
+	}
+	return lw.w.Write(q)
+}
+
+// Param is a request parameter. The parameters in a BaseString are encoded.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// BaseString holds the components of a signature base string. See
+// http://tools.ietf.org/html/rfc5849#section-3.4.1 for information about the
+// signature base string.
+type BaseString struct {
+	// Method is the uppercase request method.
+	Method string
+
+	// URI is the base string URI.
+	URI string
+
+	// Params is the list of encoded request parameters in sorted order.
+	Params []Param
+
+	// noEscape is the encoding table of the Client that created the base
+	// string.
+	noEscape *[256]bool
+
+	// lowercase is the LowercaseEscapes setting of the Client that created
+	// the base string.
+	lowercase bool
+}
+
+// BaseString returns the components of the signature base string for a
+// request with the given method, URL and parameters. The parameters from the
+// URL query string are included in the result. To get the base string used
+// in a signature, include the oauth_* protocol parameters in params.
+func (c *Client) BaseString(method string, u *url.URL, params url.Values) *BaseString {
+	b := &BaseString{
+		Method:    strings.ToUpper(method),
+		URI:       c.baseStringURI(u),
+		noEscape:  c.Compatibility.noEscape(),
+		lowercase: c.LowercaseEscapes,
+	}
+	for _, kv := range c.sortedParams(u, params, nil, false) {
+		b.Params = append(b.Params, Param{Key: string(kv.key), Value: string(kv.value)})
+	}
+	return b
+}
+
+// String returns the signature base string. The string is encoded with the
+// Compatibility and LowercaseEscapes settings of the Client that created b.
+func (b *BaseString) String() string {
+	t := b.noEscape
+	if t == nil {
+		t = &noEscape
+	}
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	if b.lowercase {
+		w = lowercaseEscapeWriter{w}
+	}
+	w.Write(encodeTable(b.Method, false, t))
+	w.Write([]byte{'&'})
+	w.Write(encodeTable(b.URI, false, t))
+	w.Write([]byte{'&'})
+	for i, p := range b.Params {
+		if i > 0 {
+			w.Write(encodedAmp)
+		}
+		w.Write(encodeTable(p.Key, false, t))
+		w.Write(encodedEqual)
+		w.Write(encodeTable(p.Value, false, t))
+	}
+	return buf.String()
+}
+
 var nonceCounter uint64
 
 func init() {
@@ -264,7 +506,10 @@ const (
 	PLAINTEXT                        // Plain text
 )
 
-// Credentials represents client, temporary and token credentials.
+// Credentials represents client, temporary and token credentials. The token
+// and secret can contain any characters, including non-ASCII and reserved
+// characters such as the '+' and '/' in base64 encoded secrets. The Client
+// encodes the values as described in section 3.6 of the RFC.
 type Credentials struct {
 	Token  string // Also known as consumer key or access token.
 	Secret string // Also known as consumer secret or access token secret.
@@ -295,6 +540,10 @@ type Client struct {
 	// request a new set of token credentials using the old set of credentials.
 	RenewCredentialRequestURI string
 
+	// RevokeTokenURI is the endpoint the client uses to invalidate a set of
+	// token credentials.
+	RevokeTokenURI string
+
 	// TemporaryCredentialsMethod is the HTTP method used by the client to
 	// obtain a set of temporary credentials. If this field is the empty
 	// string, then POST is used.
@@ -315,6 +564,445 @@ type Client struct {
 	// must be set for RSA-SHA1 signatures and ignored for other signature
 	// methods.
 	PrivateKey *rsa.PrivateKey
+
+	// MaxResponseSize is the maximum number of bytes read from the body of a
+	// response to a credentials request. If this field is zero, then
+	// DefaultMaxResponseSize is used.
+	MaxResponseSize int64
+
+	// DrainLimit is the maximum number of bytes read from a response body
+	// that the Client discards, such as the body of a redirect or of a
+	// response rejected by ResponseHook. Reading the body to the end lets
+	// the HTTP client reuse the connection. If this field is zero, then
+	// DefaultDrainLimit is used. If this field is negative, then the body is
+	// closed without reading.
+	DrainLimit int64
+
+	// ExpectContinueSize is the minimum size of a request body for which the
+	// Expect: 100-continue header is added. A provider that rejects the
+	// request, for example because of an invalid signature, responds before
+	// the body is sent. The HTTP transport must have a non-zero
+	// ExpectContinueTimeout as the transport created by NewHTTPClient does.
+	// If this field is zero, then the header is not added.
+	ExpectContinueSize int64
+
+	// RequestHook is called with each signed request before the request is
+	// sent. Changes to the request are not included in the signature. If
+	// RequestHook returns an error, then the request is not sent and the
+	// error is returned to the caller.
+	RequestHook func(*http.Request) error
+
+	// ResponseHook is called with each response before the response is
+	// returned to the caller or processed as a credentials response. If
+	// ResponseHook returns an error, then the response body is closed and the
+	// error is returned to the caller.
+	ResponseHook func(*http.Response) error
+
+	// Warning is called with a message when the application misuses a
+	// deprecated method. SignParam reports a form that already contains
+	// oauth_ parameters and a URL with a query string. If this field is nil,
+	// then misuse is not reported.
+	Warning func(msg string)
+
+	// NormalizeParam, if set, is applied to each parameter key and value
+	// before the parameter is encoded in the signature base string. The
+	// parameters sent in the request are not modified. Set this field to
+	// norm.NFC.String from golang.org/x/text/unicode/norm for providers that
+	// compute the signature over NFC normalized text.
+	NormalizeParam func(string) string
+
+	// UnescapedPath specifies that the decoded URL path is used in the
+	// signature base string. By default, the escaped path is used so that
+	// encoded reserved characters such as %2F in the path are preserved.
+	UnescapedPath bool
+
+	// RetainDefaultPort specifies that a default port present in the request
+	// URL is included in the signature base string. The RFC specifies that
+	// the default port is removed, but some providers include it.
+	RetainDefaultPort bool
+
+	// LowercaseEscapes specifies that percent encodings in the signature base
+	// string use lowercase hex digits. The RFC requires uppercase hex digits,
+	// but some providers compute the signature over lowercase encodings. The
+	// request is sent with uppercase encodings.
+	LowercaseEscapes bool
+
+	// Compatibility changes the characters that are encoded in the
+	// signature base string. If this field is nil, then the encoding in
+	// section 3.6 of the RFC is used.
+	Compatibility *Compatibility
+
+	// VerifierParam is the name of the verifier parameter for providers that
+	// do not use oauth_verifier, for example "verifier" or "pin". The
+	// verifier is sent with this name as a request parameter instead of in
+	// the Authorization header. The login package reads the verifier from
+	// the callback request parameter with this name. If this field is
+	// empty, then oauth_verifier is used.
+	VerifierParam string
+
+	// StrictMode specifies checks on the request parameters before a
+	// request is signed. If this field is nil, then the parameters are not
+	// checked.
+	StrictMode *StrictMode
+
+	// DefaultPorts maps URL schemes to default ports for the signature base
+	// string URI. The default port is removed from the base string URI. The
+	// http, https, ws and wss schemes are known. Set this field to sign
+	// requests to URLs with other schemes such as internal schemes proxied
+	// to HTTP.
+	DefaultPorts map[string]string
+
+	// URICache caches the encoded signature base string URI of endpoints.
+	// Set this field to a cache created with NewURICache for an application
+	// that signs many requests to the same endpoints. If this field is nil,
+	// then the URI is normalized and encoded for each request.
+	URICache *URICache
+
+	// RenewCredentials is called when a request sent by the Get, Post, Put or
+	// Delete methods fails with status 401 and the token_expired problem. If
+	// RenewCredentials returns new credentials, then the request is signed
+	// with the new credentials and sent once more. If RenewCredentials
+	// returns nil credentials, then the 401 response is returned. The
+	// function is responsible for saving the new credentials. A typical
+	// implementation calls RenewRequestCredentialsContext with the saved
+	// session handle.
+	RenewCredentials func(ctx context.Context, credentials *Credentials) (*Credentials, error)
+
+	// ExcludeParams is a list of request parameter names to omit from the
+	// signature base string. The parameters are still sent with the request.
+	// This field is for compatibility with providers that do not include
+	// some parameters in the signature.
+	ExcludeParams []string
+
+	// Clock returns the current time for the oauth_timestamp parameter. If
+	// this field is nil, then time.Now is used.
+	Clock func() time.Time
+
+	// Timestamp formats the time returned by Clock as the value of the
+	// oauth_timestamp parameter. If this field is nil, then the time is
+	// formatted as the number of seconds since the Unix epoch. Set this field
+	// to MillisecondTimestamp or to a function that adds a fixed offset for
+	// providers that require a different format.
+	Timestamp func(time.Time) string
+
+	// Nonce returns the value of the oauth_nonce parameter. If this field is
+	// nil, then a unique value is generated for each request. Tests set this
+	// field and the Clock field to get a repeatable signature.
+	Nonce func() string
+
+	// Rand is the source of random bytes for nonces and RSA-SHA1 signatures.
+	// If this field is set, then each nonce is read from Rand. Set this field
+	// to use a FIPS validated or hardware random number generator. If this
+	// field is nil, then nonces are generated from a counter seeded from
+	// crypto/rand and crypto/rand is used for signatures. The package does
+	// not use math/rand.
+	Rand io.Reader
+
+	// MethodOverride specifies whether requests with methods other than GET,
+	// HEAD and POST are tunneled through POST with the X-HTTP-Method-Override
+	// header and which method is signed.
+	MethodOverride MethodOverridePolicy
+
+	// RedirectPolicy specifies how redirect responses to signed requests are
+	// handled. The RedirectResign and RedirectReturn policies require Go 1.7
+	// or later when the HTTP client is an *http.Client. Other Doer
+	// implementations must not follow redirects when these policies are used.
+	RedirectPolicy RedirectPolicy
+
+	// MaxRedirects is the maximum number of redirects followed with the
+	// RedirectResign policy. If this field is zero, then 10 is used.
+	MaxRedirects int
+
+	// RedirectCrossHost specifies that the RedirectResign policy follows
+	// redirects to a different host or from https to http. By default,
+	// these redirect responses are returned to the caller because the
+	// signed request can expose secrets to the new location.
+	RedirectCrossHost bool
+
+	// RequireClientCertificate specifies that the provider requires mutual
+	// TLS. If this field is set, then requests are not sent to a URL without
+	// the https scheme or with an *http.Client that does not have a client
+	// certificate in the TLS configuration of an *http.Transport. Use
+	// NewClientCertHTTPClient to create a client with a certificate. Other
+	// Doer implementations are not checked.
+	RequireClientCertificate bool
+
+	// QueryParams specifies parameters that are added to the query string of
+	// every request sent by the Client methods and included in the signature.
+	// Use this field for providers that require an API key or client ID
+	// parameter in addition to the OAuth parameters. A parameter is not
+	// added if the request URL query already contains the parameter.
+	QueryParams url.Values
+
+	// OAuthParamPolicy specifies how parameters with the oauth_ prefix in the
+	// request URL query and form are handled. The Client adds the protocol
+	// parameters to the request. The parameters supplied by the application
+	// are usually stale copies of protocol parameters or input passed through
+	// from another source. By default, the request is rejected with an error.
+	OAuthParamPolicy OAuthParamPolicy
+
+	// VersionParam specifies whether the oauth_version parameter is sent
+	// and signed. The parameter is optional in the specification. Some
+	// providers reject requests with the parameter and others require it.
+	VersionParam VersionParam
+
+	// EmptyToken specifies how the oauth_token parameter is sent for a
+	// request without a token. The parameter is omitted from temporary
+	// credential requests and requests with nil credentials by default.
+	EmptyToken EmptyTokenPolicy
+
+	// Audit is called with credential lifecycle events: token credentials
+	// issued, renewed or revoked, rotated secrets and failed token
+	// verification. Use this field to record the events in an audit log.
+	// The function must not block.
+	Audit func(AuditEvent)
+
+	// SecretRotated is called when the provider returns a new secret for
+	// the token credentials of a request. The provider returns the secret in
+	// a renewal response with the same token or in the oauth_token_secret
+	// parameter of a form encoded response from an endpoint in
+	// SecretRotationURLs. Use this field to update stored credentials.
+	SecretRotated func(ctx context.Context, previous, rotated *Credentials)
+
+	// SecretRotationURLs is the list of API endpoints that can return a new
+	// token secret in a form encoded response. The query string is ignored.
+	// Responses from other endpoints are not checked because an endpoint
+	// that echoes user-controlled form data could replace a stored secret.
+	SecretRotationURLs []string
+
+	// HostAuthorization maps a host to the authorization scheme for requests
+	// sent to the host by the Get, Head, Post, Put and Delete methods.
+	// Requests to hosts not in the map are signed with OAuth 1.0a. Use this
+	// field in an application that calls both OAuth 1.0a and OAuth 2.0
+	// endpoints of a provider. The keys are lowercase hosts with the port if
+	// the request URL has a port.
+	HostAuthorization map[string]AuthScheme
+
+	// HeaderStyle specifies the format of the Authorization header.
+	HeaderStyle HeaderStyle
+
+	// TolerantResponses specifies that a byte order mark, surrounding white
+	// space and HTML escaped ampersands are removed from credential
+	// responses before the response is parsed. Use this field for providers
+	// that return malformed credential responses.
+	TolerantResponses bool
+
+	// ParamMethod specifies how the protocol parameters are sent by the
+	// Client methods. The application's form is not modified.
+	ParamMethod ParamMethod
+
+	// SupportBundle receives a diagnostic bundle for each request rejected
+	// by the provider because of the signature. The bundle contains the
+	// output of DumpSignedRequest for the request as sent and the response
+	// status, header and body. Secrets and the signature are redacted.
+	// Attach the bundle to a support request to the provider or to an issue
+	// against this package. Each bundle is written with one call to Write.
+	SupportBundle io.Writer
+
+	// SensitiveParams is a list of request parameter names with values that
+	// are redacted from support bundles and the output of DumpSignedRequest.
+	// The x_auth_password parameter is always redacted.
+	SensitiveParams []string
+
+	// Timeouts specifies the time limits for requests sent with a context
+	// that does not have a deadline.
+	Timeouts Timeouts
+}
+
+// EmptyTokenPolicy specifies how a Client sends an empty oauth_token
+// parameter.
+type EmptyTokenPolicy int
+
+const (
+	// EmptyTokenDefault omits oauth_token when the credentials are nil and
+	// sends the token from non-nil credentials, even if the token is empty.
+	EmptyTokenDefault EmptyTokenPolicy = iota
+
+	// EmptyTokenOmit omits oauth_token when the token is empty.
+	EmptyTokenOmit
+
+	// EmptyTokenInclude sends oauth_token="" when the credentials are nil,
+	// including requests for temporary credentials. Use this policy for
+	// gateways that require the parameter on two-legged requests.
+	EmptyTokenInclude
+)
+
+// VersionParam specifies whether a Client sends the oauth_version parameter.
+type VersionParam int
+
+const (
+	// VersionInclude sends oauth_version="1.0" with each request.
+	VersionInclude VersionParam = iota
+
+	// VersionOmit omits the oauth_version parameter.
+	VersionOmit
+)
+
+// RedirectPolicy specifies how a Client handles redirect responses.
+type RedirectPolicy int
+
+const (
+	// RedirectClient leaves redirects to the HTTP client. The *http.Client
+	// type follows redirects without signing the new request. Most providers
+	// reject the redirected request.
+	RedirectClient RedirectPolicy = iota
+
+	// RedirectResign follows redirects by signing a new request for the
+	// location in the response. A redirect to a different host or from
+	// https to http is returned to the caller unless RedirectCrossHost is
+	// set.
+	RedirectResign
+
+	// RedirectReturn returns the redirect response to the caller. The
+	// caller can get the location from the Location header.
+	RedirectReturn
+)
+
+// OAuthParamPolicy specifies how a Client handles request parameters with the
+// oauth_ prefix.
+type OAuthParamPolicy int
+
+const (
+	// OAuthParamReject returns an error for a request with a parameter
+	// that has the oauth_ prefix.
+	OAuthParamReject OAuthParamPolicy = iota
+
+	// OAuthParamStrip removes parameters with the oauth_ prefix from the
+	// request before the request is signed.
+	OAuthParamStrip
+
+	// OAuthParamAllow sends and signs parameters with the oauth_ prefix.
+	// This policy is for compatibility with providers that require the
+	// application to send an extension parameter with the oauth_ prefix.
+	OAuthParamAllow
+)
+
+// isOAuthParam returns true if key is reserved for protocol parameters.
+func isOAuthParam(key string) bool {
+	return strings.HasPrefix(key, "oauth_")
+}
+
+// checkOAuthParams applies the OAuthParamPolicy to the URL query and form in
+// r. The form and URL are copied before parameters are removed.
+func (c *Client) checkOAuthParams(r *request) error {
+	if c.OAuthParamPolicy == OAuthParamAllow {
+		return nil
+	}
+	var q url.Values
+	if r.u != nil && r.u.RawQuery != "" {
+		// Check the decoded names so that encoded names such as
+		// oauth%5Ftoken are not missed. The error is ignored as in
+		// url.URL.Query.
+		q, _ = url.ParseQuery(r.u.RawQuery)
+	}
+	for _, v := range []url.Values{q, r.form} {
+		for k := range v {
+			if isOAuthParam(k) && c.OAuthParamPolicy == OAuthParamReject {
+				return errors.New("oauth: request parameter " + k + " is reserved for the protocol")
+			}
+		}
+	}
+	if q != nil {
+		strip := false
+		for k := range q {
+			if isOAuthParam(k) {
+				delete(q, k)
+				strip = true
+			}
+		}
+		if strip {
+			u := *r.u
+			u.RawQuery = q.Encode()
+			r.u = &u
+		}
+	}
+	for k := range r.form {
+		if isOAuthParam(k) {
+			form := make(url.Values, len(r.form))
+			for k, v := range r.form {
+				if !isOAuthParam(k) {
+					form[k] = v
+				}
+			}
+			r.form = form
+			break
+		}
+	}
+	return nil
+}
+
+// DefaultMaxResponseSize is the default limit on the size of a response to a
+// credentials request.
+const DefaultMaxResponseSize = 1 << 20
+
+func (c *Client) maxResponseSize() int64 {
+	if c.MaxResponseSize > 0 {
+		return c.MaxResponseSize
+	}
+	return DefaultMaxResponseSize
+}
+
+// DefaultDrainLimit is the default limit on the number of bytes read from a
+// discarded response body.
+const DefaultDrainLimit = 64 << 10
+
+func (c *Client) drainLimit() int64 {
+	if c.DrainLimit != 0 {
+		return c.DrainLimit
+	}
+	return DefaultDrainLimit
+}
+
+// drainBody reads up to limit bytes from body and closes body. The HTTP
+// client reuses a connection only if the body was read to the end.
+func drainBody(body io.ReadCloser, limit int64) {
+	if limit > 0 {
+		io.CopyN(ioutil.Discard, body, limit)
+	}
+	body.Close()
+}
+
+func (c *Client) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+func (c *Client) timestamp() string {
+	t := c.now()
+	if c.Timestamp != nil {
+		return c.Timestamp(t)
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// MillisecondTimestamp formats t as the number of milliseconds since the Unix
+// epoch. Use it as the value of the Client Timestamp field for providers
+// that require millisecond timestamps.
+func MillisecondTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+func (c *Client) nonce() (string, error) {
+	if c.Nonce != nil {
+		return c.Nonce(), nil
+	}
+	if c.Rand != nil {
+		var b [16]byte
+		if _, err := io.ReadFull(c.Rand, b[:]); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b[:]), nil
+	}
+	return nonce(), nil
+}
+
+func (c *Client) rand() io.Reader {
+	if c.Rand != nil {
+		return c.Rand
+	}
+	return rand.Reader
 }
 
 type request struct {
@@ -325,6 +1013,8 @@ type request struct {
 	verifier      string
 	sessionHandle string
 	callbackURL   string
+	op            string // operation name for errors
+	dump          []byte // signed request description for SupportBundle
 }
 
 var testHook = func(map[string]string) {}
@@ -334,22 +1024,54 @@ var testHook = func(map[string]string) {}
 // http://tools.ietf.org/html/rfc5849#section-3.4 for more information about
 // signatures.
 func (c *Client) oauthParams(r *request) (map[string]string, error) {
+	if err := c.checkOAuthParams(r); err != nil {
+		return nil, err
+	}
+	if err := c.Compatibility.check(); err != nil {
+		return nil, err
+	}
+	if name := c.VerifierParam; r.verifier != "" && name != "" && name != "oauth_verifier" {
+		// Send the verifier as a request parameter.
+		form := make(url.Values, len(r.form)+1)
+		for k, v := range r.form {
+			form[k] = v
+		}
+		form.Set(name, r.verifier)
+		r.form = form
+	}
+	if err := c.StrictMode.check(r); err != nil {
+		return nil, err
+	}
+
 	oauthParams := map[string]string{
 		"oauth_consumer_key":     c.Credentials.Token,
 		"oauth_signature_method": c.SignatureMethod.String(),
-		"oauth_version":          "1.0",
+	}
+
+	if c.VersionParam != VersionOmit {
+		oauthParams["oauth_version"] = "1.0"
 	}
 
 	if c.SignatureMethod != PLAINTEXT {
-		oauthParams["oauth_timestamp"] = strconv.FormatInt(time.Now().Unix(), 10)
-		oauthParams["oauth_nonce"] = nonce()
+		oauthParams["oauth_timestamp"] = c.timestamp()
+		n, err := c.nonce()
+		if err != nil {
+			return nil, err
+		}
+		oauthParams["oauth_nonce"] = n
 	}
 
-	if r.credentials != nil {
+	switch {
+	case r.credentials == nil:
+		if c.EmptyToken == EmptyTokenInclude {
+			oauthParams["oauth_token"] = ""
+		}
+	case r.credentials.Token == "" && c.EmptyToken == EmptyTokenOmit:
+	default:
 		oauthParams["oauth_token"] = r.credentials.Token
 	}
 
-	if r.verifier != "" {
+	if r.verifier != "" && (c.VerifierParam == "" || c.VerifierParam == "oauth_verifier") {
 		oauthParams["oauth_verifier"] = r.verifier
 	}
 
@@ -373,15 +1095,15 @@ func (c *Client) oauthParams(r *request) (map[string]string, error) {
 			key = append(key, encode(r.credentials.Secret, false)...)
 		}
 		h := hmac.New(sha1.New, key)
-		writeBaseString(h, r.method, r.u, r.form, oauthParams)
+		c.writeBaseString(h, r.method, r.u, r.form, oauthParams)
 		signature = base64.StdEncoding.EncodeToString(h.Sum(key[:0]))
 	case RSASHA1:
 		if c.PrivateKey == nil {
 			return nil, errors.New("oauth: private key not set")
 		}
 		h := sha1.New()
-		writeBaseString(h, r.method, r.u, r.form, oauthParams)
-		rawSignature, err := rsa.SignPKCS1v15(rand.Reader, c.PrivateKey, crypto.SHA1, h.Sum(nil))
+		c.writeBaseString(h, r.method, r.u, r.form, oauthParams)
+		rawSignature, err := rsa.SignPKCS1v15(c.rand(), c.PrivateKey, crypto.SHA1, h.Sum(nil))
 		if err != nil {
 			return nil, err
 		}
@@ -401,8 +1123,59 @@ func (c *Client) oauthParams(r *request) (map[string]string, error) {
 	return oauthParams, nil
 }
 
-// SignForm adds an OAuth signature to form. The urlStr argument must not
-// include a query string.
+// SignOptions specifies options for Sign.
+type SignOptions struct {
+	// SignatureMethod is the signature method. The default is HMAC-SHA1.
+	SignatureMethod SignatureMethod
+
+	// PrivateKey is the private key for RSA-SHA1 signatures.
+	PrivateKey *rsa.PrivateKey
+
+	// Clock, Timestamp and Nonce override the timestamp and nonce as
+	// described in the documentation for the Client fields with the same
+	// names.
+	Clock     func() time.Time
+	Timestamp func(time.Time) string
+	Nonce     func() string
+
+	// HeaderStyle specifies the format of the returned header value.
+	HeaderStyle HeaderStyle
+}
+
+// Sign signs a request without a Client. Sign returns the OAuth protocol
+// parameters including the signature and the value for the Authorization
+// header. The clientCredentials are the consumer key and secret. The
+// credentials are the token credentials or nil for requests without a token.
+// The params are the form parameters; parameters in the rawURL query are
+// also included in the signature. The opts argument can be nil.
+func Sign(credentials, clientCredentials *Credentials, method, rawURL string, params url.Values, opts *SignOptions) (map[string]string, string, error) {
+	if clientCredentials == nil {
+		return nil, "", errors.New("oauth: client credentials not set")
+	}
+	c := &Client{Credentials: *clientCredentials}
+	if opts != nil {
+		c.SignatureMethod = opts.SignatureMethod
+		c.PrivateKey = opts.PrivateKey
+		c.Clock = opts.Clock
+		c.Timestamp = opts.Timestamp
+		c.Nonce = opts.Nonce
+		c.HeaderStyle = opts.HeaderStyle
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	p, err := c.oauthParams(&request{credentials: credentials, method: method, u: u, form: params})
+	if err != nil {
+		return nil, "", err
+	}
+	return p, c.HeaderStyle.format(p), nil
+}
+
+// SignForm adds an OAuth signature to form. Parameters in the urlStr query
+// string are included in the signature. The application must send the query
+// string with the request. If the form is sent in the query string, then
+// append the encoded form to the query string from urlStr.
 //
 // See http://tools.ietf.org/html/rfc5849#section-3.5.2 for
 // information about transmitting OAuth parameters in a request body and
@@ -410,29 +1183,102 @@ func (c *Client) oauthParams(r *request) (map[string]string, error) {
 // transmitting OAuth parameters in a query string.
 func (c *Client) SignForm(credentials *Credentials, method, urlStr string, form url.Values) error {
 	u, err := url.Parse(urlStr)
-	switch {
-	case err != nil:
+	if err != nil {
 		return err
-	case u.RawQuery != "":
-		return errors.New("oauth: urlStr argument to SignForm must not include a query string")
 	}
 	p, err := c.oauthParams(&request{credentials: credentials, method: method, u: u, form: form})
 	if err != nil {
 		return err
 	}
+	if c.OAuthParamPolicy == OAuthParamStrip {
+		for k := range form {
+			if isOAuthParam(k) {
+				delete(form, k)
+			}
+		}
+	}
 	for k, v := range p {
 		form.Set(k, v)
 	}
 	return nil
 }
 
-// SignParam is deprecated. Use SignForm instead.
+// ProtocolParams returns the signed OAuth protocol parameters for a request.
+// Unlike SignForm, ProtocolParams does not modify form. The application sends
+// the returned parameters with the form in the query string or request body.
+// An error is returned if form already contains a signature.
+func (c *Client) ProtocolParams(credentials *Credentials, method, urlStr string, form url.Values) (url.Values, error) {
+	if _, ok := form["oauth_signature"]; ok {
+		return nil, errors.New("oauth: form is already signed")
+	}
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.oauthParams(&request{credentials: credentials, method: method, u: u, form: form})
+	if err != nil {
+		return nil, err
+	}
+	result := make(url.Values, len(p))
+	for k, v := range p {
+		result.Set(k, v)
+	}
+	return result, nil
+}
+
+// SignURL returns urlStr with the signed OAuth protocol parameters appended
+// to the query string. Parameters in the urlStr query string are included in
+// the signature. Use SignURL for requests that cannot carry an Authorization
+// header such as image and embed URLs.
+//
+// See http://tools.ietf.org/html/rfc5849#section-3.5.3 for information about
+// transmitting OAuth parameters in a query string.
+func (c *Client) SignURL(credentials *Credentials, method, urlStr string) (string, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return "", err
+	}
+	r := &request{credentials: credentials, method: method, u: u}
+	p, err := c.oauthParams(r)
+	if err != nil {
+		return "", err
+	}
+	q := make(url.Values, len(p))
+	for k, v := range p {
+		q.Set(k, v)
+	}
+	u2 := *r.u
+	if u2.RawQuery != "" {
+		u2.RawQuery += "&"
+	}
+	u2.RawQuery += q.Encode()
+	return u2.String(), nil
+}
+
+// SignParam is deprecated. Use SignForm or ProtocolParams instead. SignParam
+// removes oauth_ parameters from params before the parameters are signed.
 func (c *Client) SignParam(credentials *Credentials, method, urlStr string, params url.Values) {
 	u, _ := url.Parse(urlStr)
+	if c.Warning != nil {
+		if u.RawQuery != "" {
+			c.Warning("oauth: SignParam does not sign the URL query string; use SignForm")
+		}
+		for k := range params {
+			if isOAuthParam(k) {
+				c.Warning("oauth: SignParam called with a form that contains " + k + "; the form is signed more than once")
+				break
+			}
+		}
+	}
 	u.RawQuery = ""
-	p, _ := c.oauthParams(&request{credentials: credentials, method: method, u: u, form: params})
-	for k, v := range p {
-		params.Set(k, v)
+	for k := range params {
+		if isOAuthParam(k) {
+			delete(params, k)
+		}
+	}
+	p, _ := c.ProtocolParams(credentials, method, u.String(), params)
+	for k := range p {
+		params.Set(k, p.Get(k))
 	}
 }
 
@@ -454,28 +1300,104 @@ func (c *Client) authorizationHeader(r *request) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	var h []byte
+	return c.HeaderStyle.format(p), nil
+}
+
+// HeaderStyle specifies the format of the Authorization header for servers
+// with strict header parsers. The zero value specifies the default format:
+// no realm, parameters separated by a comma and a space and parameters in
+// the order consumer key, nonce, signature, signature method, timestamp,
+// token, version, callback, verifier and session handle.
+type HeaderStyle struct {
+	// Realm is the value of the realm parameter. If this field is not
+	// empty, then the realm parameter is added first as a quoted string. The
+	// realm is not included in the signature.
+	Realm string
+
+	// Separator is the separator between parameters. If this field is the
+	// empty string, then ", " is used.
+	Separator string
+
+	// Sorted specifies that the oauth_* parameters are sorted by name.
+	Sorted bool
+}
+
+var sortedOAuthKeys = func() []string {
+	keys := append([]string(nil), oauthKeys...)
+	sort.Strings(keys)
+	return keys
+}()
+
+// formatAuthorizationHeader returns the authorization header value for the
+// OAuth protocol parameters p.
+func formatAuthorizationHeader(p map[string]string) string {
+	return HeaderStyle{}.format(p)
+}
+
+func (style HeaderStyle) format(p map[string]string) string {
+	sep := style.Separator
+	if sep == "" {
+		sep = ", "
+	}
 	// Append parameters in a fixed order to support testing.
-	for _, k := range oauthKeys {
+	keys := oauthKeys
+	if style.Sorted {
+		keys = sortedOAuthKeys
+	}
+
+	// Compute the size of the header to allocate the buffer once.
+	size := len("OAuth ")
+	if style.Realm != "" {
+		size += len(sep) + len(`realm=""`) + len(style.Realm) + strings.Count(style.Realm, `"`) + strings.Count(style.Realm, `\`)
+	}
+	for _, k := range keys {
 		if v, ok := p[k]; ok {
-			if h == nil {
-				h = []byte(`OAuth `)
-			} else {
-				h = append(h, ", "...)
+			size += len(sep) + len(k) + len(`=""`) + encodedLen(v, false)
+		}
+	}
+
+	h := make([]byte, 0, size)
+	h = append(h, "OAuth "...)
+	n := 0
+	add := func(k, v string) {
+		if n > 0 {
+			h = append(h, sep...)
+		}
+		n++
+		h = append(h, k...)
+		h = append(h, `="`...)
+		h = appendEncode(h, v, false)
+		h = append(h, '"')
+	}
+	if style.Realm != "" {
+		// The realm is a quoted-string as described in section 3.5.1 of
+		// the RFC.
+		h = append(h, `realm="`...)
+		for i := 0; i < len(style.Realm); i++ {
+			if b := style.Realm[i]; b == '"' || b == '\\' {
+				h = append(h, '\\')
 			}
-			h = append(h, k...)
-			h = append(h, `="`...)
-			h = append(h, encode(v, false)...)
-			h = append(h, '"')
+			h = append(h, style.Realm[i])
+		}
+		h = append(h, '"')
+		n++
+	}
+	for _, k := range keys {
+		if v, ok := p[k]; ok {
+			add(k, v)
 		}
 	}
-	return string(h), nil
+	if n == 0 {
+		return ""
+	}
+	return string(h)
 }
 
 // AuthorizationHeader returns the HTTP authorization header value for given
 // method, URL and parameters.
 //
-// AuthorizationHeader is deprecated. Use SetAuthorizationHeader instead.
+// AuthorizationHeader is deprecated. Use AuthorizationHeaderValue or
+// SetAuthorizationHeader instead.
 func (c *Client) AuthorizationHeader(credentials *Credentials, method string, u *url.URL, params url.Values) string {
 	// Signing a request can return an error. This method is deprecated because
 	// this method does not return an error.
@@ -483,12 +1405,22 @@ func (c *Client) AuthorizationHeader(credentials *Credentials, method string, u
 	return v
 }
 
+// AuthorizationHeaderValue returns the value of the HTTP Authorization header
+// for a request with the given method, URL and form. Use this method with
+// HTTP packages other than net/http.
+//
+// See http://tools.ietf.org/html/rfc5849#section-3.5.1 for information about
+// transmitting OAuth parameters in an HTTP request header.
+func (c *Client) AuthorizationHeaderValue(credentials *Credentials, method string, u *url.URL, form url.Values) (string, error) {
+	return c.authorizationHeader(&request{credentials: credentials, method: method, u: u, form: form})
+}
+
 // SetAuthorizationHeader adds an OAuth signature to a request header.
 //
 // See http://tools.ietf.org/html/rfc5849#section-3.5.1 for information about
 // transmitting OAuth parameters in an HTTP request header.
 func (c *Client) SetAuthorizationHeader(header http.Header, credentials *Credentials, method string, u *url.URL, form url.Values) error {
-	v, err := c.authorizationHeader(&request{credentials: credentials, method: method, u: u, form: form})
+	v, err := c.AuthorizationHeaderValue(credentials, method, u, form)
 	if err != nil {
 		return err
 	}
@@ -496,113 +1428,415 @@ func (c *Client) SetAuthorizationHeader(header http.Header, credentials *Credent
 	return nil
 }
 
-func (c *Client) do(ctx context.Context, urlStr string, r *request) (*http.Response, error) {
+// PreparedRequest is a signed request that can be sent more than once with
+// the same signature. Because the nonce and timestamp do not change, a
+// PreparedRequest should only be sent again when a previous attempt did not
+// reach the server. See IsNotSent.
+type PreparedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+}
+
+// NewRequest returns a new *http.Request for the prepared request. Each call
+// returns a request with a new body reader.
+func (p *PreparedRequest) NewRequest() (*http.Request, error) {
 	var body io.Reader
-	if r.method != http.MethodGet {
-		body = strings.NewReader(r.form.Encode())
+	if p.Method != http.MethodGet && p.Method != http.MethodHead {
+		body = strings.NewReader(p.Body)
 	}
-	req, err := http.NewRequest(r.method, urlStr, body)
+	req, err := http.NewRequest(p.Method, p.URL, body)
 	if err != nil {
 		return nil, err
 	}
-	if req.URL.RawQuery != "" {
-		return nil, errors.New("oauth: url must not contain a query string")
-	}
-	for k, v := range c.Header {
+	for k, v := range p.Header {
 		req.Header[k] = v
 	}
-	r.u = req.URL
-	auth, err := c.authorizationHeader(r)
+	return req, nil
+}
+
+// Prepare signs a request with the specified method and form. The form is
+// added to the URL query string for GET requests and encoded to the body for
+// other methods. Parameters in the URL query string are included in the
+// signature.
+func (c *Client) Prepare(credentials *Credentials, method, urlStr string, form url.Values) (*PreparedRequest, error) {
+	return c.prepare(urlStr, &request{method: method, credentials: credentials, form: form})
+}
+
+func (c *Client) prepare(urlStr string, r *request) (*PreparedRequest, error) {
+	u, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", auth)
-	if r.method == http.MethodGet {
-		req.URL.RawQuery = r.form.Encode()
+	if len(c.QueryParams) > 0 {
+		u = c.addQueryParams(u)
+		urlStr = u.String()
+	}
+	p := &PreparedRequest{Method: r.method, URL: urlStr, Header: make(http.Header)}
+	for k, v := range c.Header {
+		p.Header[k] = v
+	}
+	if override := c.methodOverride(r.method); override != "" {
+		p.Method = http.MethodPost
+		p.Header.Set(MethodOverrideHeader, override)
+		if c.MethodOverride == MethodOverrideSignPOST {
+			// Sign with POST. The method is restored for redirects.
+			r.method = http.MethodPost
+			defer func() { r.method = override }()
+		}
+	}
+	r.u = u
+	auth, ok, err := c.hostAuthorization(r)
+	var params map[string]string
+	if !ok && err == nil {
+		params, err = c.oauthParams(r)
+		if err == nil {
+			auth = c.HeaderStyle.format(params)
+			if c.SupportBundle != nil {
+				r.dump = c.dump(r.method, r.u, r.form, params)
+			}
+		}
+	}
+	if err != nil {
+		return nil, newOpError(r.op, r.u, err)
+	}
+	bodyParams := ""
+	if params != nil && c.ParamMethod == ParamMethodBody && r.method != http.MethodGet && r.method != http.MethodHead {
+		bodyParams = encodeProtocolParams(params)
 	} else {
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		p.Header.Set("Authorization", auth)
+	}
+	if r.u != u {
+		// The OAuthParamPolicy removed parameters from the query.
+		u = r.u
+		p.URL = u.String()
+	}
+	if r.method == http.MethodGet || r.method == http.MethodHead {
+		if q := r.form.Encode(); q != "" {
+			if u.RawQuery != "" {
+				u.RawQuery += "&"
+			}
+			u.RawQuery += q
+		}
+		p.URL = u.String()
+	} else {
+		p.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		p.Body = r.form.Encode()
+		if bodyParams != "" {
+			if p.Body != "" {
+				p.Body += "&"
+			}
+			p.Body += bodyParams
+		}
+		if c.ExpectContinueSize > 0 && int64(len(p.Body)) >= c.ExpectContinueSize {
+			p.Header.Set("Expect", "100-continue")
+		}
+	}
+	return p, nil
+}
+
+// addQueryParams returns a copy of u with the parameters in c.QueryParams
+// that are not already in the query string.
+func (c *Client) addQueryParams(u *url.URL) *url.URL {
+	q := u.Query()
+	extra := make(url.Values)
+	for k, v := range c.QueryParams {
+		if _, ok := q[k]; !ok {
+			extra[k] = v
+		}
+	}
+	u2 := *u
+	if e := extra.Encode(); e != "" {
+		if u2.RawQuery != "" {
+			u2.RawQuery += "&"
+		}
+		u2.RawQuery += e
+	}
+	return &u2
+}
+
+// isRedirect returns true if status is a redirect status.
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, 308:
+		return true
+	}
+	return false
+}
+
+// sameOrigin returns true if a redirect from u to loc stays on the same host
+// and does not downgrade from https to http.
+func sameOrigin(u, loc *url.URL) bool {
+	if !strings.EqualFold(u.Host, loc.Host) {
+		return false
+	}
+	return !(strings.EqualFold(u.Scheme, "https") && !strings.EqualFold(loc.Scheme, "https"))
+}
+
+func (c *Client) do(ctx context.Context, urlStr string, r *request) (*http.Response, error) {
+	max := c.MaxRedirects
+	if max <= 0 {
+		max = 10
+	}
+	for i := 0; ; i++ {
+		resp, err := c.send(ctx, urlStr, r)
+		if err != nil || c.RedirectPolicy != RedirectResign || !isRedirect(resp.StatusCode) {
+			return resp, err
+		}
+		loc := resp.Header.Get("Location")
+		if loc == "" {
+			return resp, nil
+		}
+		u, err := r.u.Parse(loc)
+		if err != nil {
+			drainBody(resp.Body, c.drainLimit())
+			return nil, newOpError(r.op, r.u, err)
+		}
+		if !c.RedirectCrossHost && !sameOrigin(r.u, u) {
+			return resp, nil
+		}
+		drainBody(resp.Body, c.drainLimit())
+		if i >= max {
+			return nil, newOpError(r.op, r.u, fmt.Errorf("oauth: stopped after %d redirects", max))
+		}
+		// Follow the rules used by the net/http package. The form is
+		// dropped for GET requests because the location includes the query.
+		r2 := *r
+		switch {
+		case r.method == http.MethodGet || r.method == http.MethodHead:
+			r2.form = nil
+		case resp.StatusCode != http.StatusTemporaryRedirect && resp.StatusCode != 308:
+			r2.method = http.MethodGet
+			r2.form = nil
+		}
+		urlStr, r = u.String(), &r2
+	}
+}
+
+// send signs and sends a request.
+func (c *Client) send(ctx context.Context, urlStr string, r *request) (*http.Response, error) {
+	p, err := c.prepare(urlStr, r)
+	if err != nil {
+		return nil, err
+	}
+	req, err := p.NewRequest()
+	if err != nil {
+		return nil, newOpError(r.op, r.u, err)
+	}
+	if c.RequestHook != nil {
+		if err := c.RequestHook(req); err != nil {
+			return nil, newOpError(r.op, req.URL, err)
+		}
 	}
 	req = requestWithContext(ctx, req)
 	client := contextClient(ctx)
-	return client.Do(req)
+	if c.RequireClientCertificate {
+		if err := checkClientCertificate(req.URL, client); err != nil {
+			return nil, newOpError(r.op, req.URL, err)
+		}
+	}
+	if c.RedirectPolicy != RedirectClient {
+		client = noRedirectClient(client)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, newOpError(r.op, req.URL, err)
+	}
+	if c.ResponseHook != nil {
+		if err := c.ResponseHook(resp); err != nil {
+			drainBody(resp.Body, c.drainLimit())
+			return nil, newOpError(r.op, req.URL, err)
+		}
+	}
+	if c.SupportBundle != nil && r.dump != nil {
+		c.checkSignatureFailure(r, resp)
+	}
+	return resp, nil
+}
+
+// doAPI sends an API request and renews the credentials if the server
+// reports that the token expired.
+func (c *Client) doAPI(ctx context.Context, urlStr string, r *request) (*http.Response, error) {
+	if r.credentials == nil {
+		r.credentials = CredentialsFromContext(ctx)
+	}
+	ctx, cancel := c.withTimeout(ctx, r.op)
+	resp, err := c.do(ctx, urlStr, r)
+	if err == nil && c.RenewCredentials != nil && r.credentials != nil && resp.StatusCode == http.StatusUnauthorized {
+		resp, err = c.renewAndRetry(ctx, urlStr, r, resp)
+	}
+	if err == nil && (c.SecretRotated != nil || c.Audit != nil) && r.credentials != nil && c.isSecretRotationURL(r.u) {
+		c.checkSecretRotation(ctx, r.credentials, resp)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelBody{resp.Body, cancel}
+	return resp, nil
+}
+
+// renewAndRetry renews the credentials and sends the request again if the
+// unauthorized response resp reports that the token expired.
+func (c *Client) renewAndRetry(ctx context.Context, urlStr string, r *request, resp *http.Response) (*http.Response, error) {
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize()))
+	drainBody(resp.Body, c.drainLimit())
+	if err != nil {
+		return nil, newOpError(r.op, r.u, err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(p))
+	if responseProblem(resp.Header, p) != ProblemTokenExpired {
+		return resp, nil
+	}
+	credentials, err := c.RenewCredentials(ctx, r.credentials)
+	if err != nil {
+		return nil, newOpError(r.op, r.u, err)
+	}
+	if credentials == nil {
+		return resp, nil
+	}
+	r.credentials = credentials
+	return c.do(ctx, urlStr, r)
+}
+
+// IsNotSent returns true if err shows that a request did not reach the
+// server because the connection could not be established.
+func IsNotSent(err error) bool {
+	if oe, ok := err.(*OpError); ok {
+		err = oe.Err
+	}
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+	switch err := err.(type) {
+	case *net.DNSError:
+		return true
+	case *net.OpError:
+		return err.Op == "dial"
+	}
+	return false
 }
 
 // Get issues a GET to the specified URL with form added as a query string.
-func (c *Client) Get(client *http.Client, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
+func (c *Client) Get(client Doer, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.GetContext(ctx, credentials, urlStr, form)
 }
 
 // GetContext uses Context to perform Get.
 func (c *Client) GetContext(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
-	return c.do(ctx, urlStr, &request{method: http.MethodGet, credentials: credentials, form: form})
+	return c.doAPI(ctx, urlStr, &request{op: "api_call", method: http.MethodGet, credentials: credentials, form: form})
+}
+
+// Head issues a HEAD to the specified URL with form added as a query string
+// and returns the response status code and header. There is no response body
+// to read or close. Use Head for inexpensive existence checks.
+func (c *Client) Head(client Doer, credentials *Credentials, urlStr string, form url.Values) (int, http.Header, error) {
+	ctx := context.WithValue(context.Background(), HTTPClient, client)
+	return c.HeadContext(ctx, credentials, urlStr, form)
+}
+
+// HeadContext uses Context to perform Head.
+func (c *Client) HeadContext(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (int, http.Header, error) {
+	resp, err := c.doAPI(ctx, urlStr, &request{op: "api_call", method: http.MethodHead, credentials: credentials, form: form})
+	if err != nil {
+		return 0, nil, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header, nil
 }
 
 // Post issues a POST with the specified form.
-func (c *Client) Post(client *http.Client, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
+func (c *Client) Post(client Doer, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.PostContext(ctx, credentials, urlStr, form)
 }
 
 // PostContext uses Context to perform Post.
 func (c *Client) PostContext(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
-	return c.do(ctx, urlStr, &request{method: http.MethodPost, credentials: credentials, form: form})
+	return c.doAPI(ctx, urlStr, &request{op: "api_call", method: http.MethodPost, credentials: credentials, form: form})
 }
 
 // Delete issues a DELETE with the specified form.
-func (c *Client) Delete(client *http.Client, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
+func (c *Client) Delete(client Doer, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.DeleteContext(ctx, credentials, urlStr, form)
 }
 
 // DeleteContext uses Context to perform Delete.
 func (c *Client) DeleteContext(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
-	return c.do(ctx, urlStr, &request{method: http.MethodDelete, credentials: credentials, form: form})
+	return c.doAPI(ctx, urlStr, &request{op: "api_call", method: http.MethodDelete, credentials: credentials, form: form})
 }
 
 // Put issues a PUT with the specified form.
-func (c *Client) Put(client *http.Client, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
+func (c *Client) Put(client Doer, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.PutContext(ctx, credentials, urlStr, form)
 }
 
 // PutContext uses Context to perform Put.
 func (c *Client) PutContext(ctx context.Context, credentials *Credentials, urlStr string, form url.Values) (*http.Response, error) {
-	return c.do(ctx, urlStr, &request{method: http.MethodPut, credentials: credentials, form: form})
+	return c.doAPI(ctx, urlStr, &request{op: "api_call", method: http.MethodPut, credentials: credentials, form: form})
 }
 
 func (c *Client) requestCredentials(ctx context.Context, u string, r *request) (*Credentials, url.Values, error) {
 	if r.method == "" {
 		r.method = http.MethodPost
 	}
+	ctx, cancel := c.withTimeout(ctx, r.op)
+	defer cancel()
 	resp, err := c.do(ctx, u, r)
 	if err != nil {
 		return nil, nil, err
 	}
-	p, err := ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
+	max := c.maxResponseSize()
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	drainBody(resp.Body, c.drainLimit())
 	if err != nil {
 		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
-			Body: p, msg: err.Error()}
+			Body: p, msg: err.Error(), err: newOpError(r.op, r.u, err)}
+	}
+	if int64(len(p)) > max {
+		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
+			Body: p[:max], msg: fmt.Sprintf("oauth: response body exceeds %d bytes", max)}
 	}
 	if resp.StatusCode != 200 && resp.StatusCode != 201 {
 		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
 			Body: p, msg: fmt.Sprintf("OAuth server status %d, %s", resp.StatusCode, string(p))}
 	}
-	m, err := url.ParseQuery(string(p))
+	body := p
+	if c.TolerantResponses {
+		body = cleanCredentialsResponse(p)
+	}
+	m, err := parseCredentialsResponse(resp.Header, body)
 	if err != nil {
 		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
-			Body: p, msg: err.Error()}
+			Body: p, msg: err.Error() + contentTypeNote(resp.Header) + bodyNote(p), err: err}
 	}
 	tokens := m["oauth_token"]
 	if len(tokens) == 0 || tokens[0] == "" {
 		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
-			Body: p, msg: "oauth: token missing from server result"}
+			Body: p, msg: "oauth: token missing from server result" + contentTypeNote(resp.Header) + bodyNote(p)}
 	}
 	secrets := m["oauth_token_secret"]
 	if len(secrets) == 0 { // allow "" as a valid secret.
 		return nil, nil, RequestCredentialsError{StatusCode: resp.StatusCode, Header: resp.Header,
-			Body: p, msg: "oauth: secret missing from server result"}
+			Body: p, msg: "oauth: secret missing from server result" + contentTypeNote(resp.Header) + bodyNote(p)}
+	}
+	switch r.op {
+	case "access_token":
+		c.audit(AuditIssued, tokens[0], "", nil)
+	case "renew_token":
+		previous := ""
+		if r.credentials != nil {
+			previous = r.credentials.Token
+		}
+		c.audit(AuditRenewed, tokens[0], previous, nil)
+		if r.credentials != nil && tokens[0] == r.credentials.Token && secrets[0] != r.credentials.Secret {
+			c.secretRotated(ctx, r.credentials, &Credentials{Token: tokens[0], Secret: secrets[0]})
+		}
 	}
 	return &Credentials{Token: tokens[0], Secret: secrets[0]}, m, nil
 }
@@ -610,7 +1844,7 @@ func (c *Client) requestCredentials(ctx context.Context, u string, r *request) (
 // RequestTemporaryCredentials requests temporary credentials from the server.
 // See http://tools.ietf.org/html/rfc5849#section-2.1 for information about
 // temporary credentials.
-func (c *Client) RequestTemporaryCredentials(client *http.Client, callbackURL string, additionalParams url.Values) (*Credentials, error) {
+func (c *Client) RequestTemporaryCredentials(client Doer, callbackURL string, additionalParams url.Values) (*Credentials, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.RequestTemporaryCredentialsContext(ctx, callbackURL, additionalParams)
 }
@@ -618,14 +1852,18 @@ func (c *Client) RequestTemporaryCredentials(client *http.Client, callbackURL st
 // RequestTemporaryCredentialsContext uses Context to perform RequestTemporaryCredentials.
 func (c *Client) RequestTemporaryCredentialsContext(ctx context.Context, callbackURL string, additionalParams url.Values) (*Credentials, error) {
 	credentials, _, err := c.requestCredentials(ctx, c.TemporaryCredentialRequestURI,
-		&request{method: c.TemporaryCredentialsMethod, form: additionalParams, callbackURL: callbackURL})
+		&request{op: "request_token", method: c.TemporaryCredentialsMethod, form: additionalParams, callbackURL: callbackURL})
 	return credentials, err
 }
 
 // RequestToken requests token credentials from the server. See
 // http://tools.ietf.org/html/rfc5849#section-2.3 for information about token
 // credentials.
-func (c *Client) RequestToken(client *http.Client, temporaryCredentials *Credentials, verifier string) (*Credentials, url.Values, error) {
+//
+// The response body is parsed as a form. If the response Content-Type is
+// JSON, then the body is parsed as a JSON object and the members are returned
+// as values. The same rules apply to the other credential requests.
+func (c *Client) RequestToken(client Doer, temporaryCredentials *Credentials, verifier string) (*Credentials, url.Values, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.RequestTokenContext(ctx, temporaryCredentials, verifier)
 }
@@ -633,25 +1871,25 @@ func (c *Client) RequestToken(client *http.Client, temporaryCredentials *Credent
 // RequestTokenContext uses Context to perform RequestToken.
 func (c *Client) RequestTokenContext(ctx context.Context, temporaryCredentials *Credentials, verifier string) (*Credentials, url.Values, error) {
 	return c.requestCredentials(ctx, c.TokenRequestURI,
-		&request{credentials: temporaryCredentials, method: c.TokenCredentailsMethod, verifier: verifier})
+		&request{op: "access_token", credentials: temporaryCredentials, method: c.TokenCredentailsMethod, verifier: verifier})
 }
 
 // RenewRequestCredentials requests new token credentials from the server.
 // See http://wiki.oauth.net/w/page/12238549/ScalableOAuth#AccessTokenRenewal
 // for information about access token renewal.
-func (c *Client) RenewRequestCredentials(client *http.Client, credentials *Credentials, sessionHandle string) (*Credentials, url.Values, error) {
+func (c *Client) RenewRequestCredentials(client Doer, credentials *Credentials, sessionHandle string) (*Credentials, url.Values, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.RenewRequestCredentialsContext(ctx, credentials, sessionHandle)
 }
 
 // RenewRequestCredentialsContext uses Context to perform RenewRequestCredentials.
 func (c *Client) RenewRequestCredentialsContext(ctx context.Context, credentials *Credentials, sessionHandle string) (*Credentials, url.Values, error) {
-	return c.requestCredentials(ctx, c.RenewCredentialRequestURI, &request{credentials: credentials, sessionHandle: sessionHandle})
+	return c.requestCredentials(ctx, c.RenewCredentialRequestURI, &request{op: "renew_token", credentials: credentials, sessionHandle: sessionHandle})
 }
 
 // RequestTokenXAuth requests token credentials from the server using the xAuth protocol.
 // See https://dev.twitter.com/oauth/xauth for information on xAuth.
-func (c *Client) RequestTokenXAuth(client *http.Client, temporaryCredentials *Credentials, user, password string) (*Credentials, url.Values, error) {
+func (c *Client) RequestTokenXAuth(client Doer, temporaryCredentials *Credentials, user, password string) (*Credentials, url.Values, error) {
 	ctx := context.WithValue(context.Background(), HTTPClient, client)
 	return c.RequestTokenXAuthContext(ctx, temporaryCredentials, user, password)
 }
@@ -663,34 +1901,277 @@ func (c *Client) RequestTokenXAuthContext(ctx context.Context, temporaryCredenti
 	form.Set("x_auth_username", user)
 	form.Set("x_auth_password", password)
 	return c.requestCredentials(ctx, c.TokenRequestURI,
-		&request{credentials: temporaryCredentials, method: c.TokenCredentailsMethod, form: form})
+		&request{op: "access_token", credentials: temporaryCredentials, method: c.TokenCredentailsMethod, form: form})
+}
+
+// Errors returned by ValidateToken.
+var (
+	ErrTokenInvalid = errors.New("oauth: token invalid")
+	ErrTokenExpired = errors.New("oauth: token expired")
+	ErrTokenRevoked = errors.New("oauth: token revoked")
+)
+
+// ValidateToken checks token credentials by sending a signed GET request to
+// the provider's verify credentials endpoint. ValidateToken returns nil if
+// the request succeeds. If the provider rejects the credentials, then
+// ValidateToken returns ErrTokenExpired, ErrTokenRevoked or ErrTokenInvalid.
+func (c *Client) ValidateToken(client Doer, credentials *Credentials, verifyURL string) error {
+	ctx := context.WithValue(context.Background(), HTTPClient, client)
+	return c.ValidateTokenContext(ctx, credentials, verifyURL)
+}
+
+// ValidateTokenContext uses Context to perform ValidateToken.
+func (c *Client) ValidateTokenContext(ctx context.Context, credentials *Credentials, verifyURL string) error {
+	resp, p, err := c.doRead(ctx, verifyURL, &request{op: "validate_token", method: http.MethodGet, credentials: credentials})
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		err := ErrTokenInvalid
+		switch responseProblem(resp.Header, p) {
+		case ProblemTokenExpired:
+			err = ErrTokenExpired
+		case ProblemTokenRevoked:
+			err = ErrTokenRevoked
+		}
+		if credentials != nil {
+			c.audit(AuditVerificationFailed, credentials.Token, "", err)
+		}
+		return err
+	}
+	return &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: p,
+		msg: fmt.Sprintf("oauth: verify credentials returned status %d, %s", resp.StatusCode, p)}
+}
+
+// RevokeToken invalidates token credentials using the RevokeTokenURI
+// endpoint. The application should delete saved copies of the credentials
+// after the token is revoked.
+func (c *Client) RevokeToken(client Doer, credentials *Credentials) error {
+	ctx := context.WithValue(context.Background(), HTTPClient, client)
+	return c.RevokeTokenContext(ctx, credentials)
+}
+
+// RevokeTokenContext uses Context to perform RevokeToken.
+func (c *Client) RevokeTokenContext(ctx context.Context, credentials *Credentials) error {
+	if c.RevokeTokenURI == "" {
+		return errors.New("oauth: RevokeTokenURI not set")
+	}
+	resp, p, err := c.doRead(ctx, c.RevokeTokenURI, &request{op: "revoke_token", method: http.MethodPost, credentials: credentials})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: p,
+			msg: fmt.Sprintf("oauth: revoke token returned status %d, %s", resp.StatusCode, p)}
+	}
+	if credentials != nil {
+		c.audit(AuditRevoked, credentials.Token, "", nil)
+	}
+	return nil
+}
+
+// doRead sends a request and reads the response body.
+func (c *Client) doRead(ctx context.Context, urlStr string, r *request) (*http.Response, []byte, error) {
+	ctx, cancel := c.withTimeout(ctx, r.op)
+	defer cancel()
+	resp, err := c.do(ctx, urlStr, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize()))
+	drainBody(resp.Body, c.drainLimit())
+	if err != nil {
+		return nil, nil, newOpError(r.op, r.u, err)
+	}
+	return resp, p, nil
+}
+
+// ResponseProblem returns the oauth_problem value from the WWW-Authenticate
+// header or the body of a response or "" if the response does not report a
+// problem. See http://wiki.oauth.net/w/page/12238543/ProblemReporting for
+// information about problem reporting.
+func ResponseProblem(header http.Header, body []byte) Problem {
+	return responseProblem(header, body)
+}
+
+// responseProblem returns the oauth_problem value from the WWW-Authenticate
+// header or the body of a response. See
+// http://wiki.oauth.net/w/page/12238543/ProblemReporting for information
+// about problem reporting.
+func responseProblem(header http.Header, body []byte) Problem {
+	if v := findProblem(header.Get("WWW-Authenticate")); v != "" {
+		return Problem(v)
+	}
+	return Problem(findProblem(string(body)))
+}
+
+func findProblem(s string) string {
+	const key = "oauth_problem="
+	i := strings.Index(s, key)
+	if i < 0 {
+		return ""
+	}
+	s = strings.TrimPrefix(s[i+len(key):], `"`)
+	if i := strings.IndexAny(s, "\"&, \t\r\n"); i >= 0 {
+		s = s[:i]
+	}
+	v, err := url.QueryUnescape(s)
+	if err != nil {
+		return s
+	}
+	return v
 }
 
 // AuthorizationURL returns the URL for resource owner authorization. See
 // http://tools.ietf.org/html/rfc5849#section-2.2 for information about
 // resource owner authorization.
+//
+// The additionalParams are added to the URL query string and are not signed.
+// Use additionalParams for provider options such as Access=Full. Parameters
+// that the provider expects in the signed temporary credentials request are
+// passed to RequestTemporaryCredentials instead. A query string in
+// ResourceOwnerAuthorizationURI is preserved.
 func (c *Client) AuthorizationURL(temporaryCredentials *Credentials, additionalParams url.Values) string {
 	params := make(url.Values)
 	for k, vs := range additionalParams {
 		params[k] = vs
 	}
 	params.Set("oauth_token", temporaryCredentials.Token)
-	return c.ResourceOwnerAuthorizationURI + "?" + params.Encode()
+	sep := "?"
+	if strings.Contains(c.ResourceOwnerAuthorizationURI, "?") {
+		sep = "&"
+	}
+	return c.ResourceOwnerAuthorizationURI + sep + params.Encode()
+}
+
+// Doer executes HTTP requests. The *http.Client type implements Doer. The
+// methods that send requests accept a Doer so that App Engine URL Fetch
+// clients, instrumented clients and test doubles can be used. If the Doer is
+// nil, then a shared client created with NewHTTPClient is used.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
 }
 
 // HTTPClient is the context key to use with context's
-// WithValue function to associate an *http.Client value with a context.
+// WithValue function to associate an *http.Client or other Doer value with a
+// context.
 var HTTPClient contextKey
 
 type contextKey struct{}
 
-func contextClient(ctx context.Context) *http.Client {
+func contextClient(ctx context.Context) Doer {
 	if ctx != nil {
-		if hc, ok := ctx.Value(HTTPClient).(*http.Client); ok && hc != nil {
+		switch hc := ctx.Value(HTTPClient).(type) {
+		case *http.Client:
+			if hc != nil {
+				return hc
+			}
+		case Doer:
 			return hc
 		}
 	}
-	return http.DefaultClient
+	return defaultClient
+}
+
+var defaultClient = NewHTTPClient()
+
+// NewHTTPClient returns an HTTP client with timeouts for connecting to a
+// server and waiting for a response, connection reuse and the proxy from the
+// environment. The client does not limit the time to read the response body.
+func NewHTTPClient() *http.Client {
+	return &http.Client{Transport: newTransport()}
+}
+
+// NewProxyHTTPClient returns a client created by NewHTTPClient that sends all
+// requests through the forward proxy at proxyURL. Include the user name and
+// password in proxyURL for a proxy that requires authentication. The proxy
+// credentials are sent in the Proxy-Authorization header and do not change
+// the OAuth signature. The signature is computed for the URL of the origin
+// server.
+func NewProxyHTTPClient(proxyURL *url.URL) *http.Client {
+	t := newTransport()
+	t.Proxy = http.ProxyURL(proxyURL)
+	return &http.Client{Transport: t}
+}
+
+// NewClientCertHTTPClient returns a client created by NewHTTPClient that
+// presents cert to servers that request a client certificate. If rootCAs is
+// not nil, then the client verifies server certificates using rootCAs
+// instead of the system roots.
+func NewClientCertHTTPClient(cert tls.Certificate, rootCAs *x509.CertPool) *http.Client {
+	t := newTransport()
+	t.TLSClientConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+	}
+	return &http.Client{Transport: t}
+}
+
+// checkClientCertificate returns an error if a request to u with client
+// will not present a client certificate.
+func checkClientCertificate(u *url.URL, client Doer) error {
+	if u.Scheme != "https" {
+		return errors.New("oauth: client certificate required, but " + u.Scheme + " URL " + u.Host + " does not use TLS")
+	}
+	hc, ok := client.(*http.Client)
+	if !ok {
+		return nil
+	}
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	if t.TLSClientConfig == nil || !hasClientCertificate(t.TLSClientConfig) {
+		return errors.New("oauth: client certificate required, but the HTTP client transport TLSClientConfig does not have a certificate; use NewClientCertHTTPClient or set the HTTP client in the context")
+	}
+	return nil
+}
+
+// Dialer dials network connections. The *net.Dialer type and the dialers
+// returned by the golang.org/x/net/proxy package implement Dialer.
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// NewDialerHTTPClient returns a client created by NewHTTPClient that
+// connects to servers using d. Use this function to send requests through a
+// SOCKS5 proxy such as Tor. The proxy from the environment is not used. If d
+// has a DialContext method, then that method is used in Go 1.7 and later.
+//
+//	d, err := proxy.SOCKS5("tcp", "127.0.0.1:9050", nil, proxy.Direct)
+//	ctx := context.WithValue(ctx, oauth.HTTPClient, oauth.NewDialerHTTPClient(d))
+//	tempCred, err := c.RequestTemporaryCredentialsContext(ctx, "oob", nil)
+func NewDialerHTTPClient(d Dialer) *http.Client {
+	t := newTransport()
+	t.Proxy = nil
+	setDialer(t, d)
+	return &http.Client{Transport: t}
+}
+
+type credentialsKey struct{}
+
+// WithCredentials returns a copy of ctx with the token credentials. The
+// GetContext, HeadContext, PostContext, PutContext and DeleteContext methods
+// use the credentials from the context when the credentials argument is nil.
+func WithCredentials(ctx context.Context, credentials *Credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, credentials)
+}
+
+// CredentialsFromContext returns the credentials stored in ctx by
+// WithCredentials or nil if there are no credentials in the context.
+func CredentialsFromContext(ctx context.Context) *Credentials {
+	if ctx == nil {
+		return nil
+	}
+	credentials, _ := ctx.Value(credentialsKey{}).(*Credentials)
+	return credentials
 }
 
 // RequestCredentialsError is an error containing
@@ -700,8 +2181,15 @@ type RequestCredentialsError struct {
 	Header     http.Header
 	Body       []byte
 	msg        string
+	err        error
 }
 
 func (e RequestCredentialsError) Error() string {
 	return e.msg
 }
+
+// Unwrap returns the error that caused the failure or nil if the server
+// response is the cause.
+func (e RequestCredentialsError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GetAs issues a GET using GetContext and decodes the JSON response body to a
+// value of type T.
+func GetAs[T any](ctx context.Context, c *Client, credentials *Credentials, urlStr string, form url.Values) (T, error) {
+	return decodeAs[T](c, "GET", urlStr)(c.GetContext(ctx, credentials, urlStr, form))
+}
+
+// PostAs issues a POST using PostContext and decodes the JSON response body
+// to a value of type T.
+func PostAs[T any](ctx context.Context, c *Client, credentials *Credentials, urlStr string, form url.Values) (T, error) {
+	return decodeAs[T](c, "POST", urlStr)(c.PostContext(ctx, credentials, urlStr, form))
+}
+
+// PutAs issues a PUT using PutContext and decodes the JSON response body to a
+// value of type T.
+func PutAs[T any](ctx context.Context, c *Client, credentials *Credentials, urlStr string, form url.Values) (T, error) {
+	return decodeAs[T](c, "PUT", urlStr)(c.PutContext(ctx, credentials, urlStr, form))
+}
+
+// DeleteAs issues a DELETE using DeleteContext and decodes the JSON response
+// body to a value of type T.
+func DeleteAs[T any](ctx context.Context, c *Client, credentials *Credentials, urlStr string, form url.Values) (T, error) {
+	return decodeAs[T](c, "DELETE", urlStr)(c.DeleteContext(ctx, credentials, urlStr, form))
+}
+
+// decodeAs returns a function that decodes the JSON body of the response to
+// a request with method and urlStr to a value of type T. An error is returned
+// if the response status is not 2xx. The body of an error response is read
+// up to the client's MaxResponseSize. The remainder of the body is drained up
+// to the client's DrainLimit so that the connection can be reused.
+func decodeAs[T any](c *Client, method, urlStr string) func(*http.Response, error) (T, error) {
+	return func(resp *http.Response, err error) (T, error) {
+		var v T
+		if err != nil {
+			return v, err
+		}
+		defer drainBody(resp.Body, c.drainLimit())
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			p, _ := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize()))
+			return v, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: p,
+				msg: fmt.Sprintf("oauth: %s %s returned status %d, %s", method, urlStr, resp.StatusCode, p)}
+		}
+		err = json.NewDecoder(resp.Body).Decode(&v)
+		return v, err
+	}
+}
+
+// newTransport returns a copy of http.DefaultTransport with a limit on the
+// time to wait for response headers.
+func newTransport() *http.Transport {
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		// The application replaced the default transport.
+		t = &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}
+	}
+	t = t.Clone()
+	t.MaxIdleConnsPerHost = 10
+	t.ResponseHeaderTimeout = 30 * time.Second
+	return t
+}
+
+func hasClientCertificate(cfg *tls.Config) bool {
+	return len(cfg.Certificates) > 0 || cfg.GetClientCertificate != nil
+}
@@ -0,0 +1,153 @@
+//go:build go1.18
+// +build go1.18
+
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGetAs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("got method %s, want %s", r.Method, http.MethodGet)
+		}
+		io.WriteString(w, `{"id": 10, "name": "`+r.FormValue("name")+`"}`)
+	}))
+	defer ts.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	c := Client{}
+	u, err := GetAs[user](context.Background(), &c, &Credentials{}, ts.URL, url.Values{"name": {"gopher"}})
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if u.ID != 10 || u.Name != "gopher" {
+		t.Errorf("got %+v, want {ID:10 Name:gopher}", u)
+	}
+}
+
+func TestPostAs_Status(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("got method %s, want %s", r.Method, http.MethodPost)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "forbidden")
+	}))
+	defer ts.Close()
+
+	c := Client{}
+	_, err := PostAs[map[string]interface{}](context.Background(), &c, &Credentials{}, ts.URL, nil)
+	if err == nil {
+		t.Error("error should not be nil")
+	}
+}
+
+func TestErrorClass_Is(t *testing.T) {
+	var err error = RequestCredentialsError{StatusCode: http.StatusUnauthorized}
+	if !errors.Is(err, ErrAuth) || errors.Is(err, ErrTemporary) {
+		t.Errorf("errors.Is did not classify %v as ErrAuth", err)
+	}
+	err = fmt.Errorf("wrapped: %w", &StatusError{StatusCode: http.StatusBadGateway})
+	if !errors.Is(err, ErrTemporary) || !IsTemporary(err) {
+		t.Errorf("%v not classified as ErrTemporary", err)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	tr, ok := NewHTTPClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", NewHTTPClient().Transport)
+	}
+	if tr.ResponseHeaderTimeout == 0 || tr.Proxy == nil || !tr.ForceAttemptHTTP2 {
+		t.Errorf("transport not configured: %+v", tr)
+	}
+}
+
+func TestRequireClientCertificate(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			t.Error("no client certificate")
+		}
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	c := Client{RequireClientCertificate: true}
+	_, err := c.Get(ts.Client(), &Credentials{}, ts.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), "client certificate required") {
+		t.Errorf("Get without certificate returned error %v, want client certificate required", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	resp, err := c.Get(NewClientCertHTTPClient(ts.TLS.Certificates[0], roots), &Credentials{}, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if _, err := c.Get(nil, &Credentials{}, "http://example.com/", nil); err == nil {
+		t.Error("Get with http URL did not return error")
+	}
+}
+
+// TestWasmBuild checks that the package builds for WebAssembly so that
+// browsers and edge runtimes can sign requests.
+func TestWasmBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build in short mode")
+	}
+	goTool := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goTool); err != nil {
+		t.Skipf("go tool not found: %v", err)
+	}
+	cmd := exec.Command(goTool, "build", "-o", os.DevNull, ".")
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("build for js/wasm failed: %v\n%s", err, out)
+	}
+}
+
+func TestGetAs_StatusWithoutRequest(t *testing.T) {
+	ctx := context.WithValue(context.Background(), HTTPClient, doerFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("not found, with details")),
+		}, nil
+	}))
+	c := Client{MaxResponseSize: 9}
+	_, err := GetAs[map[string]interface{}](ctx, &c, &Credentials{}, "http://example.com/user", nil)
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("returned error %v, want *StatusError", err)
+	}
+	if string(se.Body) != "not found" {
+		t.Errorf("body %q, want %q", se.Body, "not found")
+	}
+	if want := "oauth: GET http://example.com/user returned status 404, not found"; se.Error() != want {
+		t.Errorf("error %q, want %q", se.Error(), want)
+	}
+}
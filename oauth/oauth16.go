@@ -3,7 +3,10 @@
 package oauth
 
 import (
+	"crypto/tls"
+	"net"
 	"net/http"
+	"time"
 
 	"golang.org/x/net/context"
 )
@@ -11,3 +14,29 @@ import (
 func requestWithContext(ctx context.Context, req *http.Request) *http.Request {
 	return req
 }
+
+func noRedirectClient(client Doer) Doer {
+	return client
+}
+
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		MaxIdleConnsPerHost:   10,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+func setDialer(t *http.Transport, d Dialer) {
+	t.Dial = d.Dial
+}
+
+func hasClientCertificate(cfg *tls.Config) bool {
+	return len(cfg.Certificates) > 0
+}
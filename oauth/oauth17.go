@@ -4,9 +4,36 @@ package oauth
 
 import (
 	"context"
+	"net"
 	"net/http"
 )
 
 func requestWithContext(ctx context.Context, req *http.Request) *http.Request {
 	return req.WithContext(ctx)
 }
+
+// noRedirectClient returns a copy of an *http.Client that does not follow
+// redirects.
+func noRedirectClient(client Doer) Doer {
+	hc, ok := client.(*http.Client)
+	if !ok {
+		return client
+	}
+	hc2 := *hc
+	hc2.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &hc2
+}
+
+func setDialer(t *http.Transport, d Dialer) {
+	if cd, ok := d.(interface {
+		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	}); ok {
+		t.DialContext = cd.DialContext
+		return
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return d.Dial(network, addr)
+	}
+}
@@ -4,8 +4,12 @@ package oauth
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -22,3 +26,100 @@ func TestGetContext_Cancel(t *testing.T) {
 		t.Error("error should not be nil")
 	}
 }
+
+func TestRedirectPolicy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new?x=1", http.StatusSeeOther)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/new":
+			fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.RawQuery, r.Header.Get("Authorization"))
+		}
+	}))
+	defer ts.Close()
+
+	n := 0
+	c := Client{
+		RedirectPolicy: RedirectResign,
+		Nonce: func() string {
+			n++
+			return fmt.Sprint(n)
+		},
+	}
+	resp, err := c.Post(nil, &Credentials{}, ts.URL+"/old", url.Values{"a": {"b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if s := string(p); !strings.HasPrefix(s, "GET x=1 ") || !strings.Contains(s, `oauth_nonce="2"`) {
+		t.Errorf("redirected request %q, want signed GET with second nonce", s)
+	}
+
+	c.RedirectPolicy = RedirectReturn
+	resp, err = c.Get(nil, &Credentials{}, ts.URL+"/old", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/new?x=1" {
+		t.Errorf("got status %d, location %q, want redirect response", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	c.RedirectPolicy = RedirectResign
+	c.MaxRedirects = 1
+	if _, err := c.Get(nil, &Credentials{}, ts.URL+"/loop", nil); err == nil {
+		t.Error("redirect loop did not return error")
+	}
+}
+
+func TestRedirectCrossHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/new", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	c := Client{RedirectPolicy: RedirectResign}
+	resp, err := c.Get(nil, &Credentials{}, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("cross-host redirect status %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	c.RedirectCrossHost = true
+	resp, err = c.Get(nil, &Credentials{}, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(string(p), "OAuth ") {
+		t.Errorf("cross-host redirect with RedirectCrossHost sent Authorization %q, want signed request", p)
+	}
+
+	for _, tt := range []struct {
+		from, to string
+		want     bool
+	}{
+		{"https://example.com/a", "https://example.com/b", true},
+		{"http://example.com/a", "https://EXAMPLE.com/b", true},
+		{"https://example.com/a", "http://example.com/b", false},
+		{"https://example.com/a", "https://example.com:8443/b", false},
+		{"https://example.com/a", "https://api.example.com/b", false},
+	} {
+		from, _ := url.Parse(tt.from)
+		to, _ := url.Parse(tt.to)
+		if got := sameOrigin(from, to); got != tt.want {
+			t.Errorf("sameOrigin(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
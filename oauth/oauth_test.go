@@ -16,16 +16,29 @@ package oauth
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 )
@@ -200,7 +213,8 @@ func TestBaseString(t *testing.T) {
 			"oauth_version":          "1.0",
 		}
 		var buf bytes.Buffer
-		writeBaseString(&buf, ot.method, ot.url, ot.form, oauthParams)
+		c := Client{}
+		c.writeBaseString(&buf, ot.method, ot.url, ot.form, oauthParams)
 		base := buf.String()
 		if base != ot.base {
 			t.Errorf("base string for %s %s\n    = %q,\n want %q", ot.method, ot.url, base, ot.base)
@@ -308,6 +322,26 @@ func TestRequestToken(t *testing.T) {
 	}
 }
 
+func TestRequestToken_VerifierParam(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); strings.Contains(auth, "oauth_verifier") {
+			t.Errorf("auth header %q contains oauth_verifier", auth)
+		}
+		if pin := r.FormValue("pin"); pin != "verifier" {
+			t.Errorf("%s pin = %q, want verifier", r.Method, pin)
+		}
+		io.WriteString(w, "oauth_token=token&oauth_token_secret=secret")
+	}))
+	defer ts.Close()
+
+	for _, method := range []string{"GET", "POST"} {
+		c := Client{TokenRequestURI: ts.URL, TokenCredentailsMethod: method, VerifierParam: "pin"}
+		if _, _, err := c.RequestToken(http.DefaultClient, &Credentials{}, "verifier"); err != nil {
+			t.Errorf("%s returned error %v", method, err)
+		}
+	}
+}
+
 func TestRenewRequestCredentials(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		a := r.Header.Get("Authorization")
@@ -389,6 +423,40 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestHead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("got method %s, want HEAD", r.Method)
+		}
+		if r.ContentLength > 0 {
+			t.Errorf("HEAD request has body")
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") {
+			t.Errorf("HEAD request not signed")
+		}
+		if r.URL.Query().Get("id") == "missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, "body")
+	}))
+	defer ts.Close()
+
+	c := Client{MethodOverride: MethodOverrideSignPOST}
+	status, header, err := c.Head(nil, &Credentials{}, ts.URL, url.Values{"id": {"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || header.Get("ETag") != `"v1"` {
+		t.Errorf("Head returned %d %v, want 200 with ETag", status, header)
+	}
+	status, _, err = c.Head(nil, &Credentials{}, ts.URL, url.Values{"id": {"missing"}})
+	if err != nil || status != http.StatusNotFound {
+		t.Errorf("Head returned %d, %v, want 404", status, err)
+	}
+}
+
 func TestGet_ClientNil(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -482,3 +550,1225 @@ func TestRequestCredentialsError(t *testing.T) {
 		t.Error("error should be assertable RequestCredentialsError")
 	}
 }
+
+func TestRequestCredentials_JSON(t *testing.T) {
+	var contentType, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	c := Client{TokenRequestURI: ts.URL}
+	contentType = "application/json; charset=utf-8"
+	body = `{"oauth_token": "token", "oauth_token_secret": "secret", "user_id": 1234, "verified": true, "extra": null, "list": [1]}`
+	cred, values, err := c.RequestToken(nil, &Credentials{}, "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.Token != "token" || cred.Secret != "secret" {
+		t.Errorf("credentials %v, want token, secret", cred)
+	}
+	want := url.Values{
+		"oauth_token":        {"token"},
+		"oauth_token_secret": {"secret"},
+		"user_id":            {"1234"},
+		"verified":           {"true"},
+		"list":               {"[1]"},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("values %v, want %v", values, want)
+	}
+
+	for _, tt := range []struct {
+		contentType, body, err string
+	}{
+		{"application/json", `oauth_token=token`, "cannot parse JSON credentials response"},
+		{"text/html", `<html>error</html>`, "token missing from server result (response content type text/html)"},
+		{"text/plain", `oauth_secret=x`, "token missing from server result"},
+	} {
+		contentType, body = tt.contentType, tt.body
+		_, _, err := c.RequestToken(nil, &Credentials{}, "verifier")
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s %s returned error %v, want %q", tt.contentType, tt.body, err, tt.err)
+		}
+	}
+}
+
+func TestRequestCredentials_TolerantResponses(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	for _, body = range []string{
+		"\xef\xbb\xbfoauth_token=token&oauth_token_secret=secret",
+		"\r\n  oauth_token=token&oauth_token_secret=secret\n",
+		"oauth_token=token&amp;oauth_token_secret=secret",
+	} {
+		c := Client{TokenRequestURI: ts.URL}
+		cred, _, err := c.RequestToken(nil, &Credentials{}, "verifier")
+		if err == nil && *cred == (Credentials{Token: "token", Secret: "secret"}) {
+			t.Errorf("body %q parsed without TolerantResponses", body)
+		}
+		c.TolerantResponses = true
+		cred, _, err = c.RequestToken(nil, &Credentials{}, "verifier")
+		if err != nil {
+			t.Errorf("body %q returned error %v", body, err)
+			continue
+		}
+		if *cred != (Credentials{Token: "token", Secret: "secret"}) {
+			t.Errorf("body %q returned %v, want token, secret", body, cred)
+		}
+	}
+
+	c := Client{TokenRequestURI: ts.URL, TolerantResponses: true}
+	body = "<p>Service unavailable</p>"
+	if _, _, err := c.RequestToken(nil, &Credentials{}, "verifier"); err == nil || !strings.Contains(err.Error(), `body "<p>Service unavailable</p>"`) {
+		t.Errorf("error %v does not show the body", err)
+	}
+	body = "oauth_token_secret=hidden"
+	if _, _, err := c.RequestToken(nil, &Credentials{}, "verifier"); err == nil || strings.Contains(err.Error(), "hidden") {
+		t.Errorf("error %v shows the token secret", err)
+	}
+}
+
+func TestRequestCredentials_MaxResponseSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "oauth_token=token&oauth_token_secret=secret&padding=")
+		io.WriteString(w, strings.Repeat("x", 100))
+	}))
+	defer ts.Close()
+
+	c := Client{TokenRequestURI: ts.URL, MaxResponseSize: 64}
+	_, _, err := c.RequestToken(http.DefaultClient, &Credentials{}, "verifier")
+	if _, ok := err.(RequestCredentialsError); !ok {
+		t.Fatalf("returned error %v, want RequestCredentialsError", err)
+	}
+
+	c.MaxResponseSize = 0
+	if _, _, err := c.RequestToken(http.DefaultClient, &Credentials{}, "verifier"); err != nil {
+		t.Errorf("returned error %v", err)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	var auth []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("returned error %v", err)
+		}
+		if form := r.PostForm.Get("status"); form != "hello" {
+			t.Errorf("form %s, want %s", form, "hello")
+		}
+		auth = append(auth, r.Header.Get("Authorization"))
+	}))
+	defer ts.Close()
+
+	c := Client{}
+	p, err := c.Prepare(&Credentials{}, http.MethodPost, ts.URL, url.Values{"status": {"hello"}})
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		req, err := p.NewRequest()
+		if err != nil {
+			t.Fatalf("returned error %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("returned error %v", err)
+		}
+		resp.Body.Close()
+	}
+	if len(auth) != 2 || auth[0] != auth[1] {
+		t.Errorf("got Authorization headers %q, want two equal headers", auth)
+	}
+}
+
+func TestIsNotSent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	urlStr := ts.URL
+	ts.Close()
+
+	c := Client{}
+	_, err := c.Post(http.DefaultClient, &Credentials{}, urlStr, nil)
+	if err == nil {
+		t.Fatal("error should not be nil")
+	}
+	if !IsNotSent(err) {
+		t.Errorf("IsNotSent(%v) = false, want true", err)
+	}
+	if IsNotSent(errors.New("other")) {
+		t.Error("IsNotSent(other) = true, want false")
+	}
+}
+
+func TestRequestHook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("X-Trace"); v != "trace" {
+			t.Errorf("X-Trace %q, want %q", v, "trace")
+		}
+		if r.Header.Get("Authorization") == "" {
+			t.Error("Authorization header missing")
+		}
+	}))
+	defer ts.Close()
+
+	c := Client{RequestHook: func(req *http.Request) error {
+		req.Header.Set("X-Trace", "trace")
+		return nil
+	}}
+	resp, err := c.Get(http.DefaultClient, &Credentials{}, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+
+	hookErr := errors.New("hook")
+	c.RequestHook = func(req *http.Request) error { return hookErr }
+	_, err = c.Get(http.DefaultClient, &Credentials{}, ts.URL, nil)
+	if oe, ok := err.(*OpError); !ok || oe.Op != "api_call" || oe.Err != hookErr {
+		t.Errorf("returned error %v, want *OpError with %v", err, hookErr)
+	}
+}
+
+func TestResponseHook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Remaining", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	errRateLimited := errors.New("rate limited")
+	c := Client{TokenRequestURI: ts.URL, ResponseHook: func(resp *http.Response) error {
+		if resp.Header.Get("X-Rate-Limit-Remaining") == "0" {
+			return errRateLimited
+		}
+		return nil
+	}}
+	_, err := c.Get(http.DefaultClient, &Credentials{}, ts.URL, nil)
+	if oe, ok := err.(*OpError); !ok || oe.Op != "api_call" || oe.Err != errRateLimited {
+		t.Errorf("Get returned error %v, want *OpError with %v", err, errRateLimited)
+	}
+	_, _, err = c.RequestToken(http.DefaultClient, &Credentials{}, "verifier")
+	if oe, ok := err.(*OpError); !ok || oe.Op != "access_token" || oe.Err != errRateLimited {
+		t.Errorf("RequestToken returned error %v, want *OpError with %v", err, errRateLimited)
+	}
+}
+
+// countingBody counts the bytes read from a response body.
+type countingBody struct {
+	io.Reader
+	n      int64
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainLimit(t *testing.T) {
+	errRejected := errors.New("rejected")
+	for _, tt := range []struct {
+		limit int64
+		want  int64
+	}{
+		{0, 8192},
+		{100, 100},
+		{-1, 0},
+	} {
+		body := &countingBody{Reader: bytes.NewReader(make([]byte, 8192))}
+		d := doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header), Body: body}, nil
+		})
+		c := Client{DrainLimit: tt.limit, ResponseHook: func(resp *http.Response) error {
+			return errRejected
+		}}
+		_, err := c.Get(d, &Credentials{}, "http://example.com/", nil)
+		if oe, ok := err.(*OpError); !ok || oe.Err != errRejected {
+			t.Fatalf("Get returned error %v, want %v", err, errRejected)
+		}
+		if body.n != tt.want || !body.closed {
+			t.Errorf("DrainLimit=%d read %d bytes, closed %v, want %d bytes, closed", tt.limit, body.n, body.closed, tt.want)
+		}
+	}
+}
+
+func TestBaseString_RepeatedKeys(t *testing.T) {
+	u := parseURL("http://example.com/search?a=y&c=1&a=z")
+	form := url.Values{"a": {"b", "a b"}, "a b": {"1"}}
+	oauthParams := map[string]string{"oauth_consumer_key": "key"}
+	want := "GET&http%3A%2F%2Fexample.com%2Fsearch&a%3Da%2520b%26a%3Db%26a%3Dy%26a%3Dz%26a%2520b%3D1%26c%3D1%26oauth_consumer_key%3Dkey"
+	var buf bytes.Buffer
+	c := Client{}
+	c.writeBaseString(&buf, "GET", u, form, oauthParams)
+	if base := buf.String(); base != want {
+		t.Errorf("base string\n    = %q,\n want %q", base, want)
+	}
+}
+
+var encodeTests = []struct {
+	s    string
+	want string
+}{
+	{"abc-._~", "abc-._~"},
+	{"a b+c", "a%20b%2Bc"},
+	{"caf\u00e9", "caf%C3%A9"},
+	{"\U0001F600", "%F0%9F%98%80"},
+	{"\xff\x00", "%FF%00"},
+}
+
+func TestEncode(t *testing.T) {
+	for _, tt := range encodeTests {
+		if got := string(encode(tt.s, false)); got != tt.want {
+			t.Errorf("encode(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestBaseString_NormalizeParam(t *testing.T) {
+	// The decomposed form of "café" is normalized to the composed form.
+	u := parseURL("http://example.com/")
+	form := url.Values{"q": {"cafe\u0301"}}
+	want := "POST&http%3A%2F%2Fexample.com%2F&q%3Dcaf%25C3%25A9"
+	var buf bytes.Buffer
+	c := Client{NormalizeParam: func(s string) string {
+		return strings.Replace(s, "e\u0301", "\u00e9", -1)
+	}}
+	c.writeBaseString(&buf, "POST", u, form, nil)
+	if base := buf.String(); base != want {
+		t.Errorf("base string\n    = %q,\n want %q", base, want)
+	}
+	if v := form.Get("q"); v != "cafe\u0301" {
+		t.Errorf("form value modified to %q", v)
+	}
+}
+
+func TestBaseStringComponents(t *testing.T) {
+	for _, ot := range oauthTests {
+		if ot.signatureMethod == PLAINTEXT {
+			continue
+		}
+		params := url.Values{
+			"oauth_consumer_key":     {ot.clientCredentials.Token},
+			"oauth_nonce":            {ot.nonce},
+			"oauth_signature_method": {ot.signatureMethod.String()},
+			"oauth_timestamp":        {ot.timestamp},
+			"oauth_token":            {ot.credentials.Token},
+			"oauth_version":          {"1.0"},
+		}
+		for k, vs := range ot.form {
+			params[k] = append(params[k], vs...)
+		}
+		c := Client{}
+		b := c.BaseString(ot.method, ot.url, params)
+		if base := b.String(); base != ot.base {
+			t.Errorf("BaseString(%s, %s).String()\n    = %q,\n want %q", ot.method, ot.url, base, ot.base)
+		}
+	}
+
+	c := Client{}
+	b := c.BaseString("get", parseURL("HTTP://Example.com:80/a%20b?x=1"), url.Values{"y": {"a b", "2"}})
+	want := &BaseString{
+		Method:   "GET",
+		URI:      "http://example.com/a%20b",
+		Params:   []Param{{"x", "1"}, {"y", "2"}, {"y", "a%20b"}},
+		noEscape: &noEscape,
+	}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("BaseString() = %+v, want %+v", b, want)
+	}
+}
+
+var baseStringURITests = []struct {
+	url               string
+	unescapedPath     bool
+	retainDefaultPort bool
+	want              string
+}{
+	{"http://example.com", false, false, "http://example.com/"},
+	{"http://example.com/a%2Fb/c", false, false, "http://example.com/a%2Fb/c"},
+	{"http://example.com/a%2Fb/c", true, false, "http://example.com/a/b/c"},
+	{"http://example.com/a%20b", false, false, "http://example.com/a%20b"},
+	{"http://example.com/a%20b", true, false, "http://example.com/a b"},
+	{"http://example.com", true, false, "http://example.com/"},
+	{"https://example.com:443/", false, false, "https://example.com/"},
+	{"https://example.com:443/", false, true, "https://example.com:443/"},
+	{"http://example.com:80/", false, true, "http://example.com:80/"},
+	{"http://example.com:8080/", false, false, "http://example.com:8080/"},
+	{"ws://example.com:80/stream", false, false, "ws://example.com/stream"},
+	{"wss://example.com:443/stream", false, false, "wss://example.com/stream"},
+	{"wss://example.com:443/stream", false, true, "wss://example.com:443/stream"},
+	{"WSS://Example.com:8443/stream", false, false, "wss://example.com:8443/stream"},
+	{"internal://example.com:9000/a", false, false, "internal://example.com:9000/a"},
+}
+
+func TestBaseStringURI(t *testing.T) {
+	for _, tt := range baseStringURITests {
+		c := Client{UnescapedPath: tt.unescapedPath, RetainDefaultPort: tt.retainDefaultPort}
+		if got := c.baseStringURI(parseURL(tt.url)); got != tt.want {
+			t.Errorf("baseStringURI(%q) with UnescapedPath=%v, RetainDefaultPort=%v = %q, want %q",
+				tt.url, tt.unescapedPath, tt.retainDefaultPort, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultPorts(t *testing.T) {
+	c := Client{DefaultPorts: map[string]string{"Internal": "9000", "https": "8443"}}
+	for _, tt := range []struct{ url, want string }{
+		{"internal://example.com:9000/a", "internal://example.com/a"},
+		{"internal://example.com:9001/a", "internal://example.com:9001/a"},
+		{"https://example.com:8443/", "https://example.com/"},
+		{"https://example.com:443/", "https://example.com:443/"},
+	} {
+		if got := c.baseStringURI(parseURL(tt.url)); got != tt.want {
+			t.Errorf("baseStringURI(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRenewCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Authorization"), `oauth_token="expired"`) {
+			w.Header().Set("WWW-Authenticate", `OAuth oauth_problem="token_expired"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	renewed := 0
+	c := Client{RenewCredentials: func(ctx context.Context, credentials *Credentials) (*Credentials, error) {
+		renewed++
+		if credentials.Token != "expired" {
+			t.Errorf("renew token %q, want %q", credentials.Token, "expired")
+		}
+		return &Credentials{Token: "renewed"}, nil
+	}}
+	resp, err := c.Get(http.DefaultClient, &Credentials{Token: "expired"}, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if renewed != 1 {
+		t.Errorf("renewed %d times, want 1", renewed)
+	}
+
+	c.RenewCredentials = func(ctx context.Context, credentials *Credentials) (*Credentials, error) {
+		return nil, nil
+	}
+	resp, err = c.Get(http.DefaultClient, &Credentials{Token: "expired"}, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status %d with nil renewed credentials, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	c.RenewCredentials = nil
+	resp, err = c.Get(http.DefaultClient, &Credentials{Token: "expired"}, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestSortParams(t *testing.T) {
+	for _, n := range []int{0, 1, 5, smallParams, smallParams + 1, 100} {
+		var p byKeyValue
+		for i := 0; i < n; i++ {
+			p = append(p, keyValue{[]byte(fmt.Sprintf("k%d", (i*7)%5)), []byte(fmt.Sprintf("v%d", (i*13)%n))})
+		}
+		want := append(byKeyValue(nil), p...)
+		sort.Sort(want)
+		p.sortParams()
+		if !reflect.DeepEqual(p, want) {
+			t.Errorf("sortParams with %d parameters = %q, want %q", n, p, want)
+		}
+	}
+}
+
+func TestBaseString_ExcludeParams(t *testing.T) {
+	u := parseURL("http://example.com/?format=json")
+	form := url.Values{"a": {"1"}, "callback": {"f"}}
+	want := "GET&http%3A%2F%2Fexample.com%2F&a%3D1%26oauth_consumer_key%3Dkey"
+	var buf bytes.Buffer
+	c := Client{ExcludeParams: []string{"format", "callback"}}
+	c.writeBaseString(&buf, "GET", u, form, map[string]string{"oauth_consumer_key": "key"})
+	if base := buf.String(); base != want {
+		t.Errorf("base string\n    = %q,\n want %q", base, want)
+	}
+}
+
+func TestBaseString_LowercaseEscapes(t *testing.T) {
+	u := parseURL("http://example.com/a%2Fb")
+	form := url.Values{"v": {"\u00fc/Ab%"}}
+	for _, tt := range []struct {
+		lower bool
+		want  string
+	}{
+		{false, "GET&http%3A%2F%2Fexample.com%2Fa%252Fb&v%3D%25C3%25BC%252FAb%2525"},
+		{true, "GET&http%3a%2f%2fexample.com%2fa%252fb&v%3d%25c3%25bc%252fAb%2525"},
+	} {
+		var buf bytes.Buffer
+		c := Client{LowercaseEscapes: tt.lower}
+		c.writeBaseString(&buf, "GET", u, form, nil)
+		if base := buf.String(); base != tt.want {
+			t.Errorf("LowercaseEscapes=%v base string\n    = %q,\n want %q", tt.lower, base, tt.want)
+		}
+		if base := c.BaseString("GET", u, form).String(); base != tt.want {
+			t.Errorf("LowercaseEscapes=%v BaseString().String()\n    = %q,\n want %q", tt.lower, base, tt.want)
+		}
+	}
+}
+
+func TestSignForm_QueryString(t *testing.T) {
+	originalTestHook := testHook
+	defer func() {
+		testHook = originalTestHook
+	}()
+	testHook = func(p map[string]string) {
+		p["oauth_nonce"] = "Ix4U1Ei3RFL"
+		p["oauth_timestamp"] = "1327384901"
+	}
+
+	c := Client{Credentials: Credentials{"abcd", "efgh"}}
+	form := url.Values{"name": {"value"}}
+	if err := c.SignForm(&Credentials{"ijkl", "mnop"}, "GET", "http://EXAMPLE.COM:80/Space%20Craft?name=value", form); err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if sig, want := form.Get("oauth_signature"), "TZZ5u7qQorLnmKs+iqunb8gqkh4="; sig != want {
+		t.Errorf("signature %q, want %q", sig, want)
+	}
+}
+
+func TestPost_QueryString(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("returned error %v", err)
+		}
+		if v := r.URL.Query().Get("q"); v != "1" {
+			t.Errorf("query parameter q=%q, want %q", v, "1")
+		}
+		if v := r.PostForm.Get("f"); v != "2" {
+			t.Errorf("form parameter f=%q, want %q", v, "2")
+		}
+	}))
+	defer ts.Close()
+
+	c := Client{}
+	resp, err := c.Post(http.DefaultClient, &Credentials{}, ts.URL+"?q=1", url.Values{"f": {"2"}})
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+
+	p, err := c.Prepare(&Credentials{}, http.MethodGet, ts.URL+"?q=1", url.Values{"f": {"2"}})
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if want := ts.URL + "?q=1&f=2"; p.URL != want {
+		t.Errorf("URL %q, want %q", p.URL, want)
+	}
+}
+
+func TestProtocolParams(t *testing.T) {
+	c := Client{Credentials: Credentials{"key", "secret"}}
+	form := url.Values{"status": {"hello"}}
+	p, err := c.ProtocolParams(&Credentials{"token", "secret"}, "POST", "http://example.com/update", form)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if len(form) != 1 {
+		t.Errorf("form modified to %v", form)
+	}
+	for _, k := range []string{"oauth_consumer_key", "oauth_token", "oauth_signature", "oauth_nonce"} {
+		if p.Get(k) == "" {
+			t.Errorf("%s missing from %v", k, p)
+		}
+	}
+	if _, ok := p["status"]; ok {
+		t.Errorf("status included in %v", p)
+	}
+
+	for k, vs := range p {
+		form[k] = vs
+	}
+	if _, err := c.ProtocolParams(&Credentials{"token", "secret"}, "POST", "http://example.com/update", form); err == nil {
+		t.Error("error should not be nil for signed form")
+	}
+}
+
+func TestSignParam_Warning(t *testing.T) {
+	var warnings []string
+	c := Client{Credentials: Credentials{"key", "secret"}, Warning: func(msg string) {
+		warnings = append(warnings, msg)
+	}}
+	form := url.Values{"status": {"hello"}}
+	c.SignParam(&Credentials{"token", "secret"}, "POST", "http://example.com/update", form)
+	if len(warnings) != 0 {
+		t.Errorf("warnings %q for correct use, want none", warnings)
+	}
+	c.SignParam(&Credentials{"token", "secret"}, "POST", "http://example.com/update?x=1", form)
+	if len(warnings) != 2 {
+		t.Errorf("warnings %q for signed form and query, want 2", warnings)
+	}
+}
+
+func TestSignParam_Resign(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{"key", "secret"},
+		Nonce:       func() string { return "nonce" },
+		Clock:       func() time.Time { return time.Unix(1, 0) },
+	}
+	form := url.Values{"status": {"hello"}}
+	c.SignParam(&Credentials{"token", "secret"}, "POST", "http://example.com/update", form)
+	c.SignParam(&Credentials{"other", "secret"}, "POST", "http://example.com/update", form)
+	want, err := c.ProtocolParams(&Credentials{"other", "secret"}, "POST", "http://example.com/update", url.Values{"status": {"hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(form["oauth_signature"]) != 1 || form.Get("oauth_signature") != want.Get("oauth_signature") {
+		t.Errorf("second SignParam signature %q, want %q", form["oauth_signature"], want.Get("oauth_signature"))
+	}
+}
+
+func TestAuthorizationHeaderValue(t *testing.T) {
+	c := Client{SignatureMethod: RSASHA1}
+	if _, err := c.AuthorizationHeaderValue(&Credentials{}, "GET", parseURL("http://example.com/"), nil); err == nil {
+		t.Error("error should not be nil when private key is not set")
+	}
+
+	c = Client{Credentials: Credentials{"key", "secret"}, SignatureMethod: PLAINTEXT}
+	v, err := c.AuthorizationHeaderValue(&Credentials{"accesskey", "accesssecret"}, "GET", parseURL("http://example.com/"), nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	want := `OAuth oauth_consumer_key="key", oauth_signature="secret%26accesssecret", oauth_signature_method="PLAINTEXT", oauth_token="accesskey", oauth_version="1.0"`
+	if v != want {
+		t.Errorf("AuthorizationHeaderValue() =\n      %s\nwant: %s", v, want)
+	}
+}
+
+func TestClockNonce(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{"key", "secret"},
+		Clock:       func() time.Time { return time.Unix(1318622958, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	v1, err := c.AuthorizationHeaderValue(&Credentials{"token", "tokensecret"}, "GET", parseURL("http://example.com/"), nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	v2, _ := c.AuthorizationHeaderValue(&Credentials{"token", "tokensecret"}, "GET", parseURL("http://example.com/"), nil)
+	if v1 != v2 {
+		t.Errorf("header not repeatable,\n%s\n%s", v1, v2)
+	}
+	if !strings.Contains(v1, `oauth_nonce="nonce"`) || !strings.Contains(v1, `oauth_timestamp="1318622958"`) {
+		t.Errorf("header %s does not contain nonce and timestamp", v1)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestDoer(t *testing.T) {
+	var got *http.Request
+	d := doerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader("oauth_token=token&oauth_token_secret=secret")),
+		}, nil
+	})
+
+	c := Client{TokenRequestURI: "http://example.com/token"}
+	cred, _, err := c.RequestToken(d, &Credentials{}, "verifier")
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	if cred.Token != "token" {
+		t.Errorf("token %s, want %s", cred.Token, "token")
+	}
+	if got == nil || got.URL.String() != c.TokenRequestURI {
+		t.Errorf("request not sent with Doer")
+	}
+
+	var hc *http.Client
+	if d := contextClient(context.WithValue(context.Background(), HTTPClient, hc)); d != defaultClient {
+		t.Errorf("contextClient with nil *http.Client returned %v, want defaultClient", d)
+	}
+}
+
+func TestWithCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a := r.Header.Get("Authorization"); !strings.Contains(a, `oauth_token="context-token"`) {
+			t.Errorf("Authorization header %q should contain %q", a, `oauth_token="context-token"`)
+		}
+	}))
+	defer ts.Close()
+
+	if cred := CredentialsFromContext(context.Background()); cred != nil {
+		t.Errorf("CredentialsFromContext(background) = %v, want nil", cred)
+	}
+	ctx := WithCredentials(context.Background(), &Credentials{Token: "context-token"})
+	c := Client{}
+	resp, err := c.GetContext(ctx, nil, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("returned error %v", err)
+	}
+	resp.Body.Close()
+}
+
+var findProblemTests = []struct {
+	s    string
+	want string
+}{
+	{"", ""},
+	{"oauth_problem=token_expired", "token_expired"},
+	{"oauth_problem=token_rejected&oauth_problem_advice=login", "token_rejected"},
+	{`OAuth realm="example", oauth_problem="token_revoked"`, "token_revoked"},
+	{`OAuth oauth_problem="signature_invalid", realm="example"`, "signature_invalid"},
+}
+
+func TestFindProblem(t *testing.T) {
+	for _, tt := range findProblemTests {
+		if got := findProblem(tt.s); got != tt.want {
+			t.Errorf("findProblem(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := r.Header.Get("Authorization")
+		switch {
+		case strings.Contains(a, `oauth_token="valid"`):
+			io.WriteString(w, `{"id": 1}`)
+		case strings.Contains(a, `oauth_token="expired"`):
+			w.Header().Set("WWW-Authenticate", `OAuth oauth_problem="token_expired"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.Contains(a, `oauth_token="revoked"`):
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, "oauth_problem=token_revoked")
+		case strings.Contains(a, `oauth_token="unavailable"`):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	c := Client{}
+	for token, want := range map[string]error{
+		"valid":   nil,
+		"expired": ErrTokenExpired,
+		"revoked": ErrTokenRevoked,
+		"unknown": ErrTokenInvalid,
+	} {
+		if err := c.ValidateToken(http.DefaultClient, &Credentials{Token: token}, ts.URL); err != want {
+			t.Errorf("ValidateToken(%q) returned %v, want %v", token, err, want)
+		}
+	}
+	if err := c.ValidateToken(http.DefaultClient, &Credentials{Token: "unavailable"}, ts.URL); err == nil {
+		t.Error("error should not be nil for status 503")
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("got method %s, want %s", r.Method, http.MethodPost)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), `oauth_token="token"`) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	c := Client{}
+	if err := c.RevokeToken(http.DefaultClient, &Credentials{Token: "token"}); err == nil {
+		t.Error("error should not be nil when RevokeTokenURI is not set")
+	}
+	c.RevokeTokenURI = ts.URL
+	if err := c.RevokeToken(http.DefaultClient, &Credentials{Token: "token"}); err != nil {
+		t.Errorf("returned error %v", err)
+	}
+	if err := c.RevokeToken(http.DefaultClient, &Credentials{Token: "other"}); err == nil {
+		t.Error("error should not be nil for status 401")
+	}
+}
+
+func TestNewProxyHTTPClient(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{"key", "secret"},
+		Clock:       func() time.Time { return time.Unix(1318622958, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	cred := &Credentials{"token", "tokensecret"}
+	const origin = "http://api.example.com/1/items?x=y"
+	want, _ := c.AuthorizationHeaderValue(cred, "GET", parseURL(origin), nil)
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := parseBasicProxyAuth(r.Header.Get("Proxy-Authorization")); !ok || user != "user" || pass != "pass" {
+			http.Error(w, "proxy authentication required", http.StatusProxyAuthRequired)
+			return
+		}
+		if r.URL.String() != origin {
+			t.Errorf("proxy got URL %s, want %s", r.URL, origin)
+		}
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("proxy got Authorization\n      %s\nwant: %s", got, want)
+		}
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "pass")
+	resp, err := c.Get(NewProxyHTTPClient(proxyURL), cred, origin, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func parseBasicProxyAuth(s string) (user, pass string, ok bool) {
+	r := &http.Request{Header: http.Header{"Authorization": {s}}}
+	return r.BasicAuth()
+}
+
+type countingDialer struct {
+	n int
+}
+
+func (d *countingDialer) Dial(network, addr string) (net.Conn, error) {
+	d.n++
+	return net.Dial(network, addr)
+}
+
+func TestNewDialerHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "oauth_token=temp&oauth_token_secret=secret")
+	}))
+	defer ts.Close()
+
+	d := &countingDialer{}
+	c := Client{TemporaryCredentialRequestURI: ts.URL}
+	if _, err := c.RequestTemporaryCredentials(NewDialerHTTPClient(d), "oob", nil); err != nil {
+		t.Fatal(err)
+	}
+	if d.n != 1 {
+		t.Errorf("dialer called %d times, want 1", d.n)
+	}
+}
+
+func TestRand(t *testing.T) {
+	c := Client{Rand: bytes.NewReader(bytes.Repeat([]byte{0xab}, 16))}
+	v, err := c.AuthorizationHeaderValue(&Credentials{}, "GET", parseURL("http://example.com/"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `oauth_nonce="abababababababababababababababab"`; !strings.Contains(v, want) {
+		t.Errorf("header %s does not contain %s", v, want)
+	}
+	if _, err := c.AuthorizationHeaderValue(&Credentials{}, "GET", parseURL("http://example.com/"), nil); err == nil {
+		t.Error("error should not be nil when Rand is exhausted")
+	}
+}
+
+// TestImports checks that the package does not depend on packages outside of
+// the standard library other than the context package for Go 1.6.
+func TestImports(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		for name, f := range pkg.Files {
+			for _, spec := range f.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				if path == "golang.org/x/net/context" {
+					continue
+				}
+				if i := strings.Index(path, "/"); i >= 0 && strings.Contains(path[:i], ".") {
+					t.Errorf("%s imports non-standard package %s", name, path)
+				}
+			}
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	opts := &SignOptions{
+		Clock: func() time.Time { return time.Unix(1318622958, 0) },
+		Nonce: func() string { return "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg" },
+	}
+	c := Client{Credentials: Credentials{"xvz1evFS4wEEPTGEFPHBog", "kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw"}, Clock: opts.Clock, Nonce: opts.Nonce}
+	cred := &Credentials{"370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb", "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE"}
+	form := url.Values{"status": {"Hello Ladies + Gentlemen, a signed OAuth request!"}}
+	const urlStr = "https://api.twitter.com/1/statuses/update.json?include_entities=true"
+
+	p, header, err := Sign(cred, &c.Credentials, "POST", urlStr, form, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := c.AuthorizationHeaderValue(cred, "POST", parseURL(urlStr), form)
+	if header != want {
+		t.Errorf("header\n      %s\nwant: %s", header, want)
+	}
+	if p["oauth_signature"] != "tnnArxj06cWHq44gCs1OSKk/jLY=" {
+		t.Errorf("signature %s, want tnnArxj06cWHq44gCs1OSKk/jLY=", p["oauth_signature"])
+	}
+	if _, _, err := Sign(cred, nil, "POST", urlStr, form, nil); err == nil {
+		t.Error("error should not be nil when client credentials are nil")
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	clock := func() time.Time { return time.Unix(1318622958, 123456789) }
+	for _, tt := range []struct {
+		timestamp func(time.Time) string
+		want      string
+	}{
+		{nil, "1318622958"},
+		{MillisecondTimestamp, "1318622958123"},
+		{func(t time.Time) string { return strconv.FormatInt(t.Unix()+3600, 10) }, "1318626558"},
+	} {
+		c := Client{Clock: clock, Timestamp: tt.timestamp}
+		p, err := c.oauthParams(&request{method: "GET", u: parseURL("http://example.com/")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p["oauth_timestamp"]; got != tt.want {
+			t.Errorf("oauth_timestamp = %q, want %q", got, tt.want)
+		}
+	}
+
+	p, _, err := Sign(nil, &Credentials{}, "GET", "http://example.com/", nil, &SignOptions{Clock: clock, Timestamp: MillisecondTimestamp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p["oauth_timestamp"]; got != "1318622958123" {
+		t.Errorf("Sign oauth_timestamp = %q, want %q", got, "1318622958123")
+	}
+}
+
+func TestExpectContinueSize(t *testing.T) {
+	var gotExpect string
+	var gotBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotExpect = r.Header.Get("Expect")
+		gotBody = []byte(r.FormValue("data"))
+	}))
+	defer ts.Close()
+
+	c := Client{ExpectContinueSize: 1000}
+	data := strings.Repeat("x", 2000)
+	for _, tt := range []struct {
+		form   url.Values
+		expect string
+	}{
+		{url.Values{"data": {"small"}}, ""},
+		{url.Values{"data": {data}}, "100-continue"},
+	} {
+		p, err := c.Prepare(&Credentials{}, http.MethodPost, ts.URL, tt.form)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Header.Get("Expect"); got != tt.expect {
+			t.Errorf("Expect header %q, want %q", got, tt.expect)
+		}
+	}
+
+	resp, err := c.Post(NewHTTPClient(), &Credentials{}, ts.URL, url.Values{"data": {data}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if gotExpect != "100-continue" || string(gotBody) != data {
+		t.Errorf("server got Expect %q and %d byte body, want 100-continue and %d bytes", gotExpect, len(gotBody), len(data))
+	}
+}
+
+func TestQueryParams(t *testing.T) {
+	c := Client{
+		QueryParams: url.Values{"api_key": {"key"}},
+		Clock:       func() time.Time { return time.Unix(1, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	for _, urlStr := range []string{"http://example.com/a", "http://example.com/a?api_key=key"} {
+		p, err := c.Prepare(&Credentials{}, http.MethodPost, urlStr, url.Values{"status": {"hello"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		const wantURL = "http://example.com/a?api_key=key"
+		if p.URL != wantURL {
+			t.Errorf("URL %s, want %s", p.URL, wantURL)
+		}
+		want, _ := c.AuthorizationHeaderValue(&Credentials{}, http.MethodPost, parseURL(wantURL), url.Values{"status": {"hello"}})
+		if auth := p.Header.Get("Authorization"); auth != want {
+			t.Errorf("Authorization\n      %s\nwant: %s", auth, want)
+		}
+	}
+}
+
+func TestOAuthParamPolicy(t *testing.T) {
+	clock := func() time.Time { return time.Unix(1, 0) }
+	nonce := func() string { return "nonce" }
+	cred := &Credentials{"token", "secret"}
+	want, _ := (&Client{Clock: clock, Nonce: nonce}).AuthorizationHeaderValue(cred, http.MethodPost, parseURL("http://example.com/?a=1"), url.Values{"b": {"2"}})
+
+	for _, key := range []string{"oauth_token", "oauth_signature"} {
+		urlStr := "http://example.com/?a=1&" + key + "=stale"
+		form := url.Values{"b": {"2"}, key: {"stale"}}
+
+		c := Client{Clock: clock, Nonce: nonce}
+		if _, err := c.Prepare(cred, http.MethodPost, urlStr, nil); err == nil {
+			t.Errorf("%s in query: reject policy returned nil error", key)
+		}
+		if _, err := c.Prepare(cred, http.MethodPost, "http://example.com/", form); err == nil {
+			t.Errorf("%s in form: reject policy returned nil error", key)
+		}
+
+		c.OAuthParamPolicy = OAuthParamStrip
+		p, err := c.Prepare(cred, http.MethodPost, urlStr, form)
+		if err != nil {
+			t.Fatalf("%s: strip policy returned error %v", key, err)
+		}
+		if p.URL != "http://example.com/?a=1" {
+			t.Errorf("%s: strip policy URL %s, want http://example.com/?a=1", key, p.URL)
+		}
+		if p.Body != "b=2" {
+			t.Errorf("%s: strip policy body %s, want b=2", key, p.Body)
+		}
+		if auth := p.Header.Get("Authorization"); auth != want {
+			t.Errorf("%s: strip policy Authorization\n      %s\nwant: %s", key, auth, want)
+		}
+		if form.Get(key) != "stale" {
+			t.Errorf("%s: strip policy modified the application's form", key)
+		}
+
+		signed := url.Values{"b": {"2"}, key: {"stale"}}
+		if err := c.SignForm(cred, http.MethodPost, "http://example.com/", signed); err != nil {
+			t.Fatalf("%s: SignForm returned error %v", key, err)
+		}
+		if key == "oauth_token" && signed.Get(key) != "token" {
+			t.Errorf("%s: SignForm %s, want token", key, signed.Get(key))
+		}
+		if len(signed[key]) != 1 || signed.Get(key) == "stale" {
+			t.Errorf("%s: SignForm did not replace stale parameter, got %v", key, signed[key])
+		}
+
+		encoded := "http://example.com/?a=1&" + strings.Replace(key, "_", "%5F", 1) + "=stale"
+		p, err = c.Prepare(cred, http.MethodPost, encoded, url.Values{"b": {"2"}})
+		if err != nil {
+			t.Fatalf("%s encoded: strip policy returned error %v", key, err)
+		}
+		if p.URL != "http://example.com/?a=1" {
+			t.Errorf("%s encoded: strip policy URL %s, want http://example.com/?a=1", key, p.URL)
+		}
+		c.OAuthParamPolicy = OAuthParamReject
+		if _, err := c.Prepare(cred, http.MethodPost, encoded, nil); err == nil {
+			t.Errorf("%s encoded in query: reject policy returned nil error", key)
+		}
+
+		c.OAuthParamPolicy = OAuthParamAllow
+		p, err = c.Prepare(cred, http.MethodPost, urlStr, form)
+		if err != nil {
+			t.Fatalf("%s: allow policy returned error %v", key, err)
+		}
+		if p.URL != urlStr {
+			t.Errorf("%s: allow policy URL %s, want %s", key, p.URL, urlStr)
+		}
+	}
+}
+
+func TestVersionParam(t *testing.T) {
+	u := parseURL("http://example.com/")
+	for _, tt := range []struct {
+		v    VersionParam
+		want bool
+	}{
+		{VersionInclude, true},
+		{VersionOmit, false},
+	} {
+		c := Client{VersionParam: tt.v, Clock: func() time.Time { return time.Unix(1, 0) }, Nonce: func() string { return "nonce" }}
+		p, err := c.oauthParams(&request{method: http.MethodGet, u: u})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := p["oauth_version"]; ok != tt.want {
+			t.Errorf("VersionParam %d: oauth_version present = %v, want %v", tt.v, ok, tt.want)
+		}
+	}
+}
+
+func TestEmptyToken(t *testing.T) {
+	u := parseURL("http://example.com/")
+	for _, tt := range []struct {
+		policy      EmptyTokenPolicy
+		credentials *Credentials
+		want        bool
+	}{
+		{EmptyTokenDefault, nil, false},
+		{EmptyTokenDefault, &Credentials{}, true},
+		{EmptyTokenOmit, nil, false},
+		{EmptyTokenOmit, &Credentials{}, false},
+		{EmptyTokenOmit, &Credentials{Token: "token"}, true},
+		{EmptyTokenInclude, nil, true},
+		{EmptyTokenInclude, &Credentials{}, true},
+	} {
+		c := Client{EmptyToken: tt.policy}
+		p, err := c.oauthParams(&request{method: http.MethodGet, u: u, credentials: tt.credentials})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := p["oauth_token"]; ok != tt.want {
+			t.Errorf("EmptyToken %d, credentials %v: oauth_token present = %v, want %v", tt.policy, tt.credentials, ok, tt.want)
+		}
+	}
+}
+
+func TestNonASCIICredentials(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{"k\u00e9y+/", "a+b/c="},
+		Clock:       func() time.Time { return time.Unix(1, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	cred := &Credentials{"t\u00f6k", "\u00fc&x"}
+	u := parseURL("http://example.com/")
+
+	const baseString = "GET&http%3A%2F%2Fexample.com%2F&oauth_consumer_key%3Dk%25C3%25A9y%252B%252F%26oauth_nonce%3Dnonce%26" +
+		"oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D1%26oauth_token%3Dt%25C3%25B6k%26oauth_version%3D1.0"
+	const key = "a%2Bb%2Fc%3D&%C3%BC%26x"
+	h := hmac.New(sha1.New, []byte(key))
+	io.WriteString(h, baseString)
+	wantSignature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	p, err := c.oauthParams(&request{method: http.MethodGet, u: u, credentials: cred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p["oauth_signature"] != wantSignature {
+		t.Errorf("HMAC-SHA1 signature %s, want %s", p["oauth_signature"], wantSignature)
+	}
+	header := formatAuthorizationHeader(p)
+	for _, want := range []string{`oauth_consumer_key="k%C3%A9y%2B%2F"`, `oauth_token="t%C3%B6k"`} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header %s does not contain %s", header, want)
+		}
+	}
+
+	c.SignatureMethod = PLAINTEXT
+	p, err = c.oauthParams(&request{method: http.MethodGet, u: u, credentials: cred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p["oauth_signature"] != key {
+		t.Errorf("PLAINTEXT signature %s, want %s", p["oauth_signature"], key)
+	}
+	if want := `oauth_signature="a%252Bb%252Fc%253D%26%25C3%25BC%2526x"`; !strings.Contains(formatAuthorizationHeader(p), want) {
+		t.Errorf("header %s does not contain %s", formatAuthorizationHeader(p), want)
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+	}))
+	defer ts.Close()
+
+	c := Client{Credentials: Credentials{"key", "secret"}}
+	cred := &Credentials{"token", "secret"}
+	signed, err := c.SignURL(cred, http.MethodGet, ts.URL+"/image?size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if query.Get("size") != "large" || query.Get("oauth_token") != "token" || query.Get("oauth_signature") == "" {
+		t.Fatalf("query %v missing parameters", query)
+	}
+
+	// Recompute the signature from the received parameters.
+	c.Clock = func() time.Time {
+		sec, _ := strconv.ParseInt(query.Get("oauth_timestamp"), 10, 64)
+		return time.Unix(sec, 0)
+	}
+	c.Nonce = func() string { return query.Get("oauth_nonce") }
+	p, err := c.oauthParams(&request{credentials: cred, method: http.MethodGet, u: parseURL(ts.URL + "/image?size=large")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("oauth_signature") != p["oauth_signature"] {
+		t.Errorf("signature %s, want %s", query.Get("oauth_signature"), p["oauth_signature"])
+	}
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	for _, tt := range []struct {
+		uri  string
+		want string
+	}{
+		{"https://example.com/authorize", "https://example.com/authorize?Access=Full&Permissions=Modify&oauth_token=temp"},
+		{"https://example.com/authorize?mobile=1", "https://example.com/authorize?mobile=1&Access=Full&Permissions=Modify&oauth_token=temp"},
+	} {
+		c := Client{ResourceOwnerAuthorizationURI: tt.uri}
+		got := c.AuthorizationURL(&Credentials{Token: "temp"}, url.Values{"Access": {"Full"}, "Permissions": {"Modify"}})
+		if got != tt.want {
+			t.Errorf("AuthorizationURL() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestHeaderStyle(t *testing.T) {
+	p := map[string]string{
+		"oauth_consumer_key": "key",
+		"oauth_callback":     "oob",
+		"oauth_signature":    "a+b",
+	}
+	for _, tt := range []struct {
+		style HeaderStyle
+		want  string
+	}{
+		{HeaderStyle{}, `OAuth oauth_consumer_key="key", oauth_signature="a%2Bb", oauth_callback="oob"`},
+		{HeaderStyle{Separator: ","}, `OAuth oauth_consumer_key="key",oauth_signature="a%2Bb",oauth_callback="oob"`},
+		{HeaderStyle{Sorted: true}, `OAuth oauth_callback="oob", oauth_consumer_key="key", oauth_signature="a%2Bb"`},
+		{HeaderStyle{Realm: "http://example.com/"}, `OAuth realm="http://example.com/", oauth_consumer_key="key", oauth_signature="a%2Bb", oauth_callback="oob"`},
+		{HeaderStyle{Realm: `a "b" \c`}, `OAuth realm="a \"b\" \\c", oauth_consumer_key="key", oauth_signature="a%2Bb", oauth_callback="oob"`},
+	} {
+		if got := tt.style.format(p); got != tt.want {
+			t.Errorf("%+v.format() =\n      %s\nwant: %s", tt.style, got, tt.want)
+		}
+	}
+}
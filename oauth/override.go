@@ -0,0 +1,54 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import "net/http"
+
+// MethodOverrideHeader is the header that carries the method of a request
+// tunneled through POST.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// MethodOverridePolicy specifies how a Client sends requests with methods
+// other than GET, HEAD and POST. Some proxies and providers only accept GET,
+// HEAD and POST. These providers accept other methods tunneled through POST
+// with the method in the X-HTTP-Method-Override header. Providers differ in
+// the method used in the signature base string of a tunneled request.
+type MethodOverridePolicy int
+
+const (
+	// MethodOverrideNone sends requests with the requested method.
+	MethodOverrideNone MethodOverridePolicy = iota
+
+	// MethodOverrideSignPOST tunnels requests through POST and signs the
+	// request with POST, the method sent on the wire.
+	MethodOverrideSignPOST
+
+	// MethodOverrideSignOverride tunnels requests through POST and signs
+	// the request with the method in the override header.
+	MethodOverrideSignOverride
+)
+
+// methodOverride returns the method to send in the override header for a
+// request with method or "" if the request is not tunneled.
+func (c *Client) methodOverride(method string) string {
+	if c.MethodOverride == MethodOverrideNone {
+		return ""
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return ""
+	}
+	return method
+}
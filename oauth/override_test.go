@@ -0,0 +1,92 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestMethodOverride(t *testing.T) {
+	var req *http.Request
+	var body string
+	d := doerFunc(func(r *http.Request) (*http.Response, error) {
+		req = r
+		p, _ := ioutil.ReadAll(r.Body)
+		body = string(p)
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader("")), Request: r}, nil
+	})
+	ctx := context.WithValue(context.Background(), HTTPClient, d)
+	cred := &Credentials{Token: "token", Secret: "secret"}
+	form := url.Values{"id": {"1"}}
+	const urlStr = "https://example.com/items"
+	u, _ := url.Parse(urlStr)
+
+	for _, tt := range []struct {
+		policy     MethodOverridePolicy
+		method     string
+		wireMethod string
+		override   string
+		signMethod string
+	}{
+		{MethodOverrideNone, "DELETE", "DELETE", "", "DELETE"},
+		{MethodOverrideSignPOST, "DELETE", "POST", "DELETE", "POST"},
+		{MethodOverrideSignOverride, "DELETE", "POST", "DELETE", "DELETE"},
+		{MethodOverrideSignPOST, "PUT", "POST", "PUT", "POST"},
+		{MethodOverrideSignOverride, "PUT", "POST", "PUT", "PUT"},
+		{MethodOverrideSignPOST, "POST", "POST", "", "POST"},
+	} {
+		c := Client{
+			Credentials:    Credentials{Token: "key", Secret: "clientsecret"},
+			MethodOverride: tt.policy,
+			Clock:          func() time.Time { return time.Unix(1300000000, 0) },
+			Nonce:          func() string { return "nonce" },
+		}
+		var resp *http.Response
+		var err error
+		switch tt.method {
+		case "DELETE":
+			resp, err = c.DeleteContext(ctx, cred, urlStr, form)
+		case "PUT":
+			resp, err = c.PutContext(ctx, cred, urlStr, form)
+		case "POST":
+			resp, err = c.PostContext(ctx, cred, urlStr, form)
+		}
+		if err != nil {
+			t.Errorf("%d %s returned error %v", tt.policy, tt.method, err)
+			continue
+		}
+		resp.Body.Close()
+		if req.Method != tt.wireMethod {
+			t.Errorf("%d %s sent method %s, want %s", tt.policy, tt.method, req.Method, tt.wireMethod)
+		}
+		if got := req.Header.Get(MethodOverrideHeader); got != tt.override {
+			t.Errorf("%d %s sent override %q, want %q", tt.policy, tt.method, got, tt.override)
+		}
+		if body != "id=1" {
+			t.Errorf("%d %s sent body %q, want id=1", tt.policy, tt.method, body)
+		}
+		want, _ := c.AuthorizationHeaderValue(cred, tt.signMethod, u, form)
+		if got := req.Header.Get("Authorization"); got != want {
+			t.Errorf("%d %s sent Authorization\n      %s\nwant: %s", tt.policy, tt.method, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import "net/url"
+
+// ParamMethod specifies how a Client sends the protocol parameters. See
+// http://tools.ietf.org/html/rfc5849#section-3.5 for information about
+// parameter transmission.
+type ParamMethod int
+
+const (
+	// ParamMethodHeader sends the protocol parameters in the Authorization
+	// header.
+	ParamMethodHeader ParamMethod = iota
+
+	// ParamMethodBody sends the protocol parameters in the form encoded
+	// body of requests with a body. The parameters are sent in the
+	// Authorization header for GET and HEAD requests. This method is for
+	// providers that only verify the signature of a request with the
+	// parameters in the body.
+	ParamMethodBody
+)
+
+// encodeProtocolParams returns the form encoding of the protocol parameters
+// p for the request body.
+func encodeProtocolParams(p map[string]string) string {
+	v := make(url.Values, len(p))
+	for k, s := range p {
+		v.Set(k, s)
+	}
+	return v.Encode()
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParamMethodBody(t *testing.T) {
+	c := Client{
+		Credentials: Credentials{Token: "key", Secret: "clientsecret"},
+		ParamMethod: ParamMethodBody,
+		Clock:       func() time.Time { return time.Unix(1300000000, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	cred := &Credentials{Token: "token", Secret: "secret"}
+	form := url.Values{"status": {"hello world"}}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		want, _ := c.ProtocolParams(cred, r.Method, "http://"+r.Host+r.URL.Path, form)
+		switch r.Method {
+		case http.MethodPost:
+			if auth != "" {
+				t.Errorf("POST sent Authorization %q", auth)
+			}
+			if r.PostForm.Get("status") != "hello world" {
+				t.Errorf("POST form %v does not contain status", r.PostForm)
+			}
+			for k := range want {
+				if !reflect.DeepEqual(r.PostForm[k], want[k]) {
+					t.Errorf("POST %s = %q, want %q", k, r.PostForm[k], want[k])
+				}
+			}
+		case http.MethodGet:
+			if auth == "" {
+				t.Error("GET did not send Authorization")
+			}
+			if _, ok := r.Form["oauth_signature"]; ok {
+				t.Error("GET sent oauth_signature in the query")
+			}
+		}
+	}))
+	defer ts.Close()
+
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		var resp *http.Response
+		var err error
+		if method == http.MethodPost {
+			resp, err = c.Post(nil, cred, ts.URL+"/update", form)
+		} else {
+			resp, err = c.Get(nil, cred, ts.URL+"/show", form)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if len(form) != 1 {
+		t.Errorf("form modified to %v", form)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Params returns form parameters with the keys and values in m. Use Params to
+// pass a map[string]string to the Client methods:
+//
+//	resp, err := c.Get(nil, credentials, urlStr, oauth.Params(map[string]string{"count": "10"}))
+//
+// A nil map returns nil.
+func Params(m map[string]string) url.Values {
+	if m == nil {
+		return nil
+	}
+	v := make(url.Values, len(m))
+	for key, value := range m {
+		v[key] = []string{value}
+	}
+	return v
+}
+
+// ParamMap returns a map with the form parameters in v. ParamMap returns an
+// error if a key has more than one value because the map cannot represent
+// repeated parameters. A key with no values maps to the empty string.
+func ParamMap(v url.Values) (map[string]string, error) {
+	m := make(map[string]string, len(v))
+	var repeated []string
+	for key, values := range v {
+		switch len(values) {
+		case 0:
+			m[key] = ""
+		case 1:
+			m[key] = values[0]
+		default:
+			repeated = append(repeated, key)
+		}
+	}
+	if repeated != nil {
+		sort.Strings(repeated)
+		return nil, errors.New("oauth: parameter " + repeated[0] + " has more than one value")
+	}
+	return m, nil
+}
+
+// StructParams returns form parameters for the exported fields of the struct
+// or pointer to struct v. The parameter name is the field name or the name in
+// the field's "form" tag. The tag option "omitempty" omits the parameter when
+// the field has the zero value. The tag "-" omits the field. Fields of
+// embedded structs are treated as fields of the outer struct.
+//
+//	type statusUpdate struct {
+//		Status   string `form:"status"`
+//		ReplyTo  int64  `form:"in_reply_to_status_id,omitempty"`
+//		TrimUser bool   `form:"trim_user,omitempty"`
+//	}
+//
+// Strings, booleans, integers, floats and types that implement
+// encoding.TextMarshaler are supported. A slice adds a value for each
+// element. A nil pointer is treated as the zero value of the field.
+func StructParams(v interface{}) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("oauth: StructParams of nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("oauth: StructParams of non-struct type %s", rv.Type())
+	}
+	form := make(url.Values)
+	if err := appendStructParams(form, rv); err != nil {
+		return nil, err
+	}
+	return form, nil
+}
+
+func appendStructParams(form url.Values, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, options = tag[:i], tag[i+1:]
+		}
+		fv := rv.Field(i)
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Ptr {
+				// Nil pointer to embedded struct.
+				continue
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := appendStructParams(form, fv); err != nil {
+					return err
+				}
+				continue
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if options == "omitempty" && isZeroParam(fv) {
+			continue
+		}
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatParam(fv.Index(j))
+				if err != nil {
+					return fmt.Errorf("oauth: field %s: %v", f.Name, err)
+				}
+				form.Add(name, s)
+			}
+			continue
+		}
+		s, err := formatParam(fv)
+		if err != nil {
+			return fmt.Errorf("oauth: field %s: %v", f.Name, err)
+		}
+		form.Add(name, s)
+	}
+	return nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func formatParam(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+		} else {
+			v = v.Elem()
+		}
+	}
+	if v.CanInterface() && v.Type().Implements(textMarshalerType) {
+		p, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(p), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func isZeroParam(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return v.CanInterface() && reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParams(t *testing.T) {
+	if v := Params(nil); v != nil {
+		t.Errorf("Params(nil) = %v, want nil", v)
+	}
+	v := Params(map[string]string{"a": "1", "b": ""})
+	want := url.Values{"a": {"1"}, "b": {""}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Params() = %v, want %v", v, want)
+	}
+}
+
+func TestParamMap(t *testing.T) {
+	m, err := ParamMap(url.Values{"a": {"1"}, "b": {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"a": "1", "b": ""}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("ParamMap() = %v, want %v", m, want)
+	}
+	if _, err := ParamMap(url.Values{"a": {"1", "2"}}); err == nil {
+		t.Error("ParamMap() with repeated values returned nil error")
+	}
+}
+
+type structParamsEmbedded struct {
+	Page int `form:"page,omitempty"`
+}
+
+type structParamsTest struct {
+	structParamsEmbedded
+	Status   string    `form:"status"`
+	ReplyTo  int64     `form:"in_reply_to,omitempty"`
+	Trim     bool      `form:"trim_user"`
+	Lat      float64   `form:"lat,omitempty"`
+	IDs      []uint    `form:"id"`
+	Count    *int      `form:"count,omitempty"`
+	Since    time.Time `form:"since,omitempty"`
+	Skip     string    `form:"-"`
+	Untagged string
+	private  string
+}
+
+func TestStructParams(t *testing.T) {
+	three := 3
+	v := structParamsTest{
+		structParamsEmbedded: structParamsEmbedded{Page: 2},
+		Status:               "hello",
+		IDs:                  []uint{1, 2},
+		Lat:                  1.5,
+		Count:                &three,
+		Since:                time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skip:                 "skip",
+		Untagged:             "x",
+		private:              "private",
+	}
+	form, err := StructParams(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"page":      {"2"},
+		"status":    {"hello"},
+		"trim_user": {"false"},
+		"lat":       {"1.5"},
+		"id":        {"1", "2"},
+		"count":     {"3"},
+		"since":     {"2026-01-02T03:04:05Z"},
+		"Untagged":  {"x"},
+	}
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("StructParams() =\n%v\nwant\n%v", form, want)
+	}
+
+	form, err = StructParams(structParamsTest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = url.Values{"status": {""}, "trim_user": {"false"}, "Untagged": {""}}
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("StructParams(zero) =\n%v\nwant\n%v", form, want)
+	}
+
+	if _, err := StructParams("string"); err == nil {
+		t.Error("StructParams(string) returned nil error")
+	}
+	if _, err := StructParams(struct{ M map[string]string }{}); err == nil {
+		t.Error("StructParams(map field) returned nil error")
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RateLimit is the rate limit information in a response.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the window or -1 if not
+	// known.
+	Limit int
+
+	// Remaining is the number of requests remaining in the window or -1 if
+	// not known.
+	Remaining int
+
+	// Reset is the time that the window resets or the zero time if not
+	// known.
+	Reset time.Time
+}
+
+// ParseRateLimit returns the rate limit information in the response headers
+// X-Rate-Limit-Limit, X-Rate-Limit-Remaining and X-Rate-Limit-Reset or the
+// same headers with the X-RateLimit- prefix. The reset header is the time in
+// seconds since the Unix epoch. ParseRateLimit returns false if the headers
+// are not present.
+func ParseRateLimit(header http.Header) (RateLimit, bool) {
+	rl := RateLimit{Limit: -1, Remaining: -1}
+	found := false
+	for _, prefix := range []string{"X-Rate-Limit-", "X-Ratelimit-"} {
+		if v, err := strconv.Atoi(header.Get(prefix + "Limit")); err == nil {
+			rl.Limit = v
+			found = true
+		}
+		if v, err := strconv.Atoi(header.Get(prefix + "Remaining")); err == nil {
+			rl.Remaining = v
+			found = true
+		}
+		if v, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+			rl.Reset = time.Unix(v, 0)
+			found = true
+		}
+		if found {
+			break
+		}
+	}
+	return rl, found
+}
+
+// PoolStrategy specifies how a TokenPool selects credentials.
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin selects the credentials in turn.
+	PoolRoundRobin PoolStrategy = iota
+
+	// PoolLeastRecentlyUsed selects the credentials that were used least
+	// recently.
+	PoolLeastRecentlyUsed
+
+	// PoolLeastThrottled selects the credentials with the most requests
+	// remaining in the rate limit window. Credentials without rate limit
+	// information are selected first.
+	PoolLeastThrottled
+)
+
+// ErrPoolExhausted is returned by TokenPool when the pool is empty or all
+// credentials are rate limited.
+var ErrPoolExhausted = errors.New("oauth: no credentials available in pool")
+
+// TokenPool spreads requests across the token credentials of many users to
+// increase the aggregate rate limit for read-only API calls. The pool skips
+// credentials that are rate limited according to the rate limit headers
+// and status of the responses passed to Update. The zero value is an empty
+// pool that uses the PoolRoundRobin strategy.
+type TokenPool struct {
+	// Strategy specifies how credentials are selected.
+	Strategy PoolStrategy
+
+	// RetryAfter is the time that credentials are skipped after a rate
+	// limited response without a reset time. If this field is zero, then one
+	// minute is used.
+	RetryAfter time.Duration
+
+	mu      sync.Mutex
+	entries []*poolEntry
+	next    int
+}
+
+type poolEntry struct {
+	credentials *Credentials
+	lastUsed    time.Time
+	remaining   int
+	reset       time.Time
+}
+
+// poolNow is replaced in tests.
+var poolNow = time.Now
+
+func (p *TokenPool) retryAfter() time.Duration {
+	if p.RetryAfter > 0 {
+		return p.RetryAfter
+	}
+	return time.Minute
+}
+
+// Add adds credentials to the pool.
+func (p *TokenPool) Add(credentials *Credentials) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = append(p.entries, &poolEntry{credentials: credentials, remaining: -1})
+}
+
+// Remove removes the credentials with the token from the pool. Remove
+// credentials when the user revokes access.
+func (p *TokenPool) Remove(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, e := range p.entries {
+		if e.credentials.Token == token {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Len returns the number of credentials in the pool.
+func (p *TokenPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// available returns true if the entry is not rate limited at time now.
+func (e *poolEntry) available(now time.Time) bool {
+	return e.remaining != 0 || !now.Before(e.reset)
+}
+
+// Select returns credentials from the pool or ErrPoolExhausted.
+func (p *TokenPool) Select() (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := poolNow()
+	var best *poolEntry
+	n := len(p.entries)
+	start := p.next
+	for i := 0; i < n; i++ {
+		j := (start + i) % n
+		e := p.entries[j]
+		if !e.available(now) {
+			continue
+		}
+		if best == nil {
+			best = e
+			p.next = j + 1
+			if p.Strategy == PoolRoundRobin {
+				break
+			}
+			continue
+		}
+		switch p.Strategy {
+		case PoolLeastRecentlyUsed:
+			if e.lastUsed.Before(best.lastUsed) {
+				best = e
+			}
+		case PoolLeastThrottled:
+			if best.remaining >= 0 && (e.remaining < 0 || e.remaining > best.remaining) {
+				best = e
+			}
+		}
+	}
+	if best == nil {
+		return nil, ErrPoolExhausted
+	}
+	best.lastUsed = now
+	return best.credentials, nil
+}
+
+// Update records the rate limit information from the response to a request
+// signed with credentials. The resp argument can be nil.
+func (p *TokenPool) Update(credentials *Credentials, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	rl, ok := ParseRateLimit(resp.Header)
+	limited := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 420
+	if !ok && !limited {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.entries {
+		if e.credentials != credentials && e.credentials.Token != credentials.Token {
+			continue
+		}
+		e.remaining = rl.Remaining
+		e.reset = rl.Reset
+		if limited {
+			e.remaining = 0
+			if !rl.Reset.After(poolNow()) {
+				e.reset = poolNow().Add(p.retryAfter())
+			}
+		}
+		return
+	}
+}
+
+// Get sends a GET request using credentials selected from the pool and
+// updates the pool with the rate limit information in the response.
+func (p *TokenPool) Get(ctx context.Context, c *Client, urlStr string, form url.Values) (*http.Response, error) {
+	credentials, err := p.Select()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.GetContext(ctx, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+	p.Update(credentials, resp)
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	if _, ok := ParseRateLimit(h); ok {
+		t.Error("ParseRateLimit(empty) returned true")
+	}
+	h.Set("X-RateLimit-Limit", "150")
+	h.Set("X-RateLimit-Remaining", "7")
+	h.Set("X-RateLimit-Reset", "1700000000")
+	rl, ok := ParseRateLimit(h)
+	if !ok || rl.Limit != 150 || rl.Remaining != 7 || !rl.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("ParseRateLimit() = %+v, %v", rl, ok)
+	}
+}
+
+func selectTokens(t *testing.T, p *TokenPool, n int) string {
+	var s string
+	for i := 0; i < n; i++ {
+		c, err := p.Select()
+		if err != nil {
+			t.Fatalf("Select returned error %v", err)
+		}
+		s += c.Token
+	}
+	return s
+}
+
+func TestTokenPool(t *testing.T) {
+	now := time.Unix(1000, 0)
+	poolNow = func() time.Time { return now }
+	defer func() { poolNow = time.Now }()
+
+	var p TokenPool
+	if _, err := p.Select(); err != ErrPoolExhausted {
+		t.Fatalf("Select on empty pool returned %v, want ErrPoolExhausted", err)
+	}
+	a, b, c := &Credentials{Token: "a"}, &Credentials{Token: "b"}, &Credentials{Token: "c"}
+	p.Add(a)
+	p.Add(b)
+	p.Add(c)
+	if s := selectTokens(t, &p, 4); s != "abca" {
+		t.Errorf("round robin selected %s, want abca", s)
+	}
+
+	// Rate limit b until reset.
+	p.Update(b, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	if s := selectTokens(t, &p, 4); s != "caca" {
+		t.Errorf("round robin with b limited selected %s, want caca", s)
+	}
+	now = now.Add(2 * time.Minute)
+	if s := selectTokens(t, &p, 3); s != "bca" {
+		t.Errorf("round robin after reset selected %s, want bca", s)
+	}
+
+	p.Strategy = PoolLeastThrottled
+	for _, x := range []struct {
+		cred      *Credentials
+		remaining int
+	}{{a, 5}, {b, 50}, {c, 0}} {
+		h := http.Header{}
+		h.Set("X-Rate-Limit-Remaining", strconv.Itoa(x.remaining))
+		h.Set("X-Rate-Limit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+		p.Update(x.cred, &http.Response{StatusCode: http.StatusOK, Header: h})
+	}
+	now = now.Add(time.Second)
+	if s := selectTokens(t, &p, 2); s != "bb" {
+		t.Errorf("least throttled selected %s, want bb", s)
+	}
+
+	p.Strategy = PoolLeastRecentlyUsed
+	now = now.Add(time.Second)
+	if s := selectTokens(t, &p, 1); s != "a" {
+		t.Errorf("least recently used selected %s, want a", s)
+	}
+
+	p.Remove("a")
+	p.Remove("b")
+	if _, err := p.Select(); err != ErrPoolExhausted || !IsRateLimited(err) {
+		t.Errorf("Select with all limited returned %v, want ErrPoolExhausted", err)
+	}
+	if p.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", p.Len())
+	}
+}
+
+func TestTokenPool_Get(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Remaining", "0")
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}))
+	defer ts.Close()
+
+	var p TokenPool
+	p.Add(&Credentials{Token: "a"})
+	var c Client
+	resp, err := p.Get(context.Background(), &c, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if _, err := p.Get(context.Background(), &c, ts.URL, nil); err != ErrPoolExhausted {
+		t.Errorf("second Get returned %v, want ErrPoolExhausted", err)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+// Problem is an oauth_problem value reported by a provider. See
+// http://wiki.oauth.net/w/page/12238543/ProblemReporting for information
+// about problem reporting.
+type Problem string
+
+// Problems defined by the problem reporting extension.
+const (
+	ProblemVersionRejected                 Problem = "version_rejected"
+	ProblemParameterAbsent                 Problem = "parameter_absent"
+	ProblemParameterRejected               Problem = "parameter_rejected"
+	ProblemTimestampRefused                Problem = "timestamp_refused"
+	ProblemNonceUsed                       Problem = "nonce_used"
+	ProblemSignatureMethodRejected         Problem = "signature_method_rejected"
+	ProblemSignatureInvalid                Problem = "signature_invalid"
+	ProblemConsumerKeyUnknown              Problem = "consumer_key_unknown"
+	ProblemConsumerKeyRejected             Problem = "consumer_key_rejected"
+	ProblemConsumerKeyRefused              Problem = "consumer_key_refused"
+	ProblemTokenUsed                       Problem = "token_used"
+	ProblemTokenExpired                    Problem = "token_expired"
+	ProblemTokenRevoked                    Problem = "token_revoked"
+	ProblemTokenRejected                   Problem = "token_rejected"
+	ProblemAdditionalAuthorizationRequired Problem = "additional_authorization_required"
+	ProblemPermissionUnknown               Problem = "permission_unknown"
+	ProblemPermissionDenied                Problem = "permission_denied"
+	ProblemUserRefused                     Problem = "user_refused"
+)
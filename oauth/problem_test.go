@@ -0,0 +1,37 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResponseProblem(t *testing.T) {
+	for _, tt := range []struct {
+		header http.Header
+		body   string
+		want   Problem
+	}{
+		{http.Header{"Www-Authenticate": {`OAuth oauth_problem="token_expired"`}}, "", ProblemTokenExpired},
+		{nil, "oauth_problem=signature_invalid&oauth_problem_advice=check", ProblemSignatureInvalid},
+		{http.Header{"Www-Authenticate": {`OAuth realm="x"`}}, "oauth_problem=nonce_used", ProblemNonceUsed},
+		{nil, "error", ""},
+	} {
+		if got := ResponseProblem(tt.header, []byte(tt.body)); got != tt.want {
+			t.Errorf("ResponseProblem(%v, %q) = %q, want %q", tt.header, tt.body, got, tt.want)
+		}
+	}
+}
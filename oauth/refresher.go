@@ -0,0 +1,89 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// CredentialsRefresher keeps time-limited credentials up to date in the
+// background. It is for two-legged deployments where the provider issues
+// tickets or tokens that must be renewed periodically. Goroutines that sign
+// requests call the Credentials method to get the current credentials.
+//
+//	r := &oauth.CredentialsRefresher{Refresh: getTicket}
+//	go r.Run(ctx)
+//	...
+//	resp, err := c.Get(nil, r.Credentials(), urlStr, form)
+type CredentialsRefresher struct {
+	// Refresh returns new credentials and the time that the credentials
+	// expire.
+	Refresh func(ctx context.Context) (*Credentials, time.Time, error)
+
+	// Margin is the time before expiration that the credentials are
+	// refreshed. If this field is zero, then one minute is used.
+	Margin time.Duration
+
+	// RetryDelay is the time to wait before calling Refresh again after an
+	// error. If this field is zero, then ten seconds is used.
+	RetryDelay time.Duration
+
+	// OnError, if set, is called with each error returned by Refresh.
+	OnError func(error)
+
+	current atomic.Value // *Credentials
+}
+
+// Credentials returns the current credentials or nil if Refresh has not
+// returned credentials.
+func (r *CredentialsRefresher) Credentials() *Credentials {
+	cred, _ := r.current.Load().(*Credentials)
+	return cred
+}
+
+// Run calls Refresh until ctx is done. Run returns the context error.
+func (r *CredentialsRefresher) Run(ctx context.Context) error {
+	margin := r.Margin
+	if margin <= 0 {
+		margin = time.Minute
+	}
+	retry := r.RetryDelay
+	if retry <= 0 {
+		retry = 10 * time.Second
+	}
+	for {
+		delay := retry
+		cred, expires, err := r.Refresh(ctx)
+		if err != nil {
+			if r.OnError != nil {
+				r.OnError(err)
+			}
+		} else {
+			r.current.Store(cred)
+			delay = expires.Sub(time.Now()) - margin
+			if delay < retry {
+				delay = retry
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
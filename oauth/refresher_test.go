@@ -0,0 +1,62 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestCredentialsRefresher(t *testing.T) {
+	var n int32
+	r := &CredentialsRefresher{
+		Refresh: func(ctx context.Context) (*Credentials, time.Time, error) {
+			i := atomic.AddInt32(&n, 1)
+			if i == 2 {
+				return nil, time.Time{}, errors.New("unavailable")
+			}
+			return &Credentials{Token: strconv.Itoa(int(i))}, time.Now().Add(15 * time.Millisecond), nil
+		},
+		Margin:     5 * time.Millisecond,
+		RetryDelay: 5 * time.Millisecond,
+	}
+	if r.Credentials() != nil {
+		t.Fatal("credentials not nil before refresh")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if cred := r.Credentials(); cred != nil && cred.Token == "3" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("credentials %v not refreshed after error", r.Credentials())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/context"
+)
+
+// checkSecretRotation reports a new token secret in a form encoded API
+// response. The body of resp is replaced with a reader that returns the
+// complete body.
+func (c *Client) checkSecretRotation(ctx context.Context, credentials *Credentials, resp *http.Response) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != "application/x-www-form-urlencoded" {
+		return
+	}
+	max := c.maxResponseSize()
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(p), resp.Body), resp.Body}
+	if err != nil || int64(len(p)) > max {
+		return
+	}
+	if rotated, ok := RotatedSecret(credentials, p); ok {
+		c.secretRotated(ctx, credentials, rotated)
+	}
+}
+
+// isSecretRotationURL returns true if u is an endpoint in
+// SecretRotationURLs.
+func (c *Client) isSecretRotationURL(u *url.URL) bool {
+	if u == nil {
+		return false
+	}
+	uri := c.baseStringURI(u)
+	for _, s := range c.SecretRotationURLs {
+		if v, err := url.Parse(s); err == nil && c.baseStringURI(v) == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// RotatedSecret returns the credentials with a new secret if the form
+// encoded response body contains an oauth_token_secret parameter with a
+// secret for credentials. The response must not contain a different
+// oauth_token.
+func RotatedSecret(credentials *Credentials, body []byte) (*Credentials, bool) {
+	m, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, false
+	}
+	secrets, ok := m["oauth_token_secret"]
+	if !ok || len(secrets) != 1 || secrets[0] == credentials.Secret {
+		return nil, false
+	}
+	if tokens, ok := m["oauth_token"]; ok && (len(tokens) != 1 || tokens[0] != credentials.Token) {
+		return nil, false
+	}
+	return &Credentials{Token: credentials.Token, Secret: secrets[0]}, true
+}
+
+func (c *Client) secretRotated(ctx context.Context, previous, rotated *Credentials) {
+	c.audit(AuditSecretRotated, rotated.Token, previous.Token, nil)
+	if c.SecretRotated != nil {
+		c.SecretRotated(ctx, previous, rotated)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestSecretRotated(t *testing.T) {
+	const passwordBody = "status=ok&oauth_token_secret=new"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/password":
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+			io.WriteString(w, passwordBody)
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"oauth_token_secret":"new"}`)
+		case "/echo":
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+			io.WriteString(w, r.FormValue("echo"))
+		case "/renew":
+			io.WriteString(w, "oauth_token=token&oauth_token_secret=renewed")
+		}
+	}))
+	defer ts.Close()
+
+	var rotated []*Credentials
+	var events []AuditEventType
+	c := Client{
+		RenewCredentialRequestURI: ts.URL + "/renew",
+		SecretRotationURLs:        []string{ts.URL + "/password", ts.URL + "/json"},
+		SecretRotated: func(ctx context.Context, previous, cred *Credentials) {
+			if previous.Token != cred.Token {
+				t.Errorf("rotated token %q, want %q", cred.Token, previous.Token)
+			}
+			rotated = append(rotated, cred)
+		},
+		Audit: func(e AuditEvent) { events = append(events, e.Type) },
+	}
+	cred := &Credentials{Token: "token", Secret: "old"}
+
+	resp, err := c.Post(nil, cred, ts.URL+"/password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != passwordBody {
+		t.Errorf("body %q, want %q", body, passwordBody)
+	}
+
+	resp, err = c.Get(nil, cred, ts.URL+"/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// Endpoints that are not in SecretRotationURLs are not checked.
+	resp, err = c.Get(nil, cred, ts.URL+"/echo", url.Values{"echo": {"oauth_token_secret=attacker"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if _, _, err := c.RenewRequestCredentials(nil, cred, "handle"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rotated) != 2 || rotated[0].Secret != "new" || rotated[1].Secret != "renewed" {
+		t.Errorf("rotated %v, want secrets new and renewed", rotated)
+	}
+	want := []AuditEventType{AuditSecretRotated, AuditRenewed, AuditSecretRotated}
+	if len(events) != len(want) {
+		t.Fatalf("events %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events %v, want %v", events, want)
+			break
+		}
+	}
+
+	// Audit receives rotations without SecretRotated.
+	c.SecretRotated = nil
+	events = nil
+	resp, err = c.Post(nil, cred, ts.URL+"/password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(events) != 1 || events[0] != AuditSecretRotated {
+		t.Errorf("events %v with Audit only, want [%s]", events, AuditSecretRotated)
+	}
+}
+
+func TestRotatedSecret(t *testing.T) {
+	cred := &Credentials{Token: "token", Secret: "old"}
+	for _, tt := range []struct {
+		body string
+		want string
+	}{
+		{"oauth_token_secret=new", "new"},
+		{"oauth_token=token&oauth_token_secret=new", "new"},
+		{"oauth_token=other&oauth_token_secret=new", ""},
+		{"oauth_token_secret=old", ""},
+		{"oauth_token_secret=a&oauth_token_secret=b", ""},
+		{"status=ok", ""},
+	} {
+		got, ok := RotatedSecret(cred, []byte(tt.body))
+		switch {
+		case ok != (tt.want != ""):
+			t.Errorf("RotatedSecret(%q) returned ok %v", tt.body, ok)
+		case ok && (got.Secret != tt.want || got.Token != cred.Token):
+			t.Errorf("RotatedSecret(%q) = %v, want secret %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DefaultMaxParamLength is the default maximum length in bytes of a
+// parameter value in strict mode.
+const DefaultMaxParamLength = 4096
+
+// StrictMode specifies checks on the request parameters before a request is
+// signed. Providers often respond to a parameter they cannot handle with an
+// opaque signature error. A Client with StrictMode set returns a descriptive
+// error for these parameters instead of sending the request.
+//
+// Strict mode rejects parameter names and values with control characters
+// such as newline and NUL or invalid UTF-8, empty parameter names and values
+// longer than MaxValueLength. Parameters with the oauth_ prefix are rejected
+// if repeated or if the Client adds a protocol parameter with the same name.
+// The verifier, callback URL and session handle are also checked.
+type StrictMode struct {
+	// MaxValueLength is the maximum length in bytes of a parameter value.
+	// If this field is zero, then DefaultMaxParamLength is used.
+	MaxValueLength int
+}
+
+func (sm *StrictMode) maxValueLength() int {
+	if sm.MaxValueLength > 0 {
+		return sm.MaxValueLength
+	}
+	return DefaultMaxParamLength
+}
+
+// check returns an error if a parameter in r fails the strict mode checks.
+func (sm *StrictMode) check(r *request) error {
+	if sm == nil {
+		return nil
+	}
+	for _, p := range []struct {
+		name  string
+		value string
+	}{
+		{"oauth_callback", r.callbackURL},
+		{"oauth_verifier", r.verifier},
+		{"oauth_session_handle", r.sessionHandle},
+	} {
+		if err := sm.checkValue(p.value); err != nil {
+			return errors.New("oauth: " + p.name + " " + err.Error())
+		}
+	}
+
+	var q url.Values
+	if r.u != nil {
+		q = r.u.Query()
+	}
+	seen := make(map[string]bool)
+	for _, params := range []url.Values{q, r.form} {
+		for k, vs := range params {
+			if k == "" {
+				return errors.New("oauth: request parameter has an empty name")
+			}
+			if err := checkStrictString(k); err != nil {
+				return errors.New("oauth: name of request parameter " + strconv.Quote(k) + " " + err.Error())
+			}
+			if isOAuthParam(k) {
+				if seen[k] || len(vs) > 1 || r.setsParam(k) {
+					return errors.New("oauth: request parameter " + k + " is repeated")
+				}
+				seen[k] = true
+			}
+			for _, v := range vs {
+				if err := sm.checkValue(v); err != nil {
+					return errors.New("oauth: value of request parameter " + k + " " + err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setsParam returns true if the Client adds the protocol parameter key to
+// the request. The protocol parameters other than the callback, verifier and
+// session handle are counted as added because the Client adds them to most
+// requests.
+func (r *request) setsParam(key string) bool {
+	switch key {
+	case "oauth_callback":
+		return r.callbackURL != ""
+	case "oauth_verifier":
+		return r.verifier != ""
+	case "oauth_session_handle":
+		return r.sessionHandle != ""
+	}
+	return containsString(oauthKeys, key)
+}
+
+func (sm *StrictMode) checkValue(v string) error {
+	if n := sm.maxValueLength(); len(v) > n {
+		return errors.New("is longer than " + strconv.Itoa(n) + " bytes")
+	}
+	return checkStrictString(v)
+}
+
+// checkStrictString returns an error if s contains a control character or
+// invalid UTF-8.
+func checkStrictString(s string) error {
+	if !utf8.ValidString(s) {
+		return errors.New("contains invalid UTF-8")
+	}
+	for _, r := range s {
+		switch {
+		case r == 0:
+			return errors.New("contains NUL")
+		case r == '\n' || r == '\r':
+			return errors.New("contains a newline")
+		case r < 0x20 || r == 0x7f:
+			return errors.New("contains control character " + strconv.QuoteRune(r))
+		}
+	}
+	return nil
+}
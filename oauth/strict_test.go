@@ -0,0 +1,69 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestStrictMode(t *testing.T) {
+	u, _ := url.Parse("https://example.com/api?oauth_extra=1")
+	for _, tt := range []struct {
+		form url.Values
+		r    request
+		err  string // substring of the error, "" for no error
+	}{
+		{form: url.Values{"status": {"hello ü"}}},
+		{form: url.Values{"oauth_other": {"x"}}},
+		{form: url.Values{"status": {"a\nb"}}, err: "value of request parameter status contains a newline"},
+		{form: url.Values{"status": {"a\x00b"}}, err: "contains NUL"},
+		{form: url.Values{"status": {"a\tb"}}, err: `contains control character '\t'`},
+		{form: url.Values{"status": {"\xff"}}, err: "contains invalid UTF-8"},
+		{form: url.Values{"a\rb": {"x"}}, err: `name of request parameter "a\rb" contains a newline`},
+		{form: url.Values{"": {"x"}}, err: "empty name"},
+		{form: url.Values{"status": {strings.Repeat("x", DefaultMaxParamLength+1)}}, err: "is longer than 4096 bytes"},
+		{form: url.Values{"oauth_other": {"x", "y"}}, err: "oauth_other is repeated"},
+		{form: url.Values{"oauth_extra": {"2"}}, err: "oauth_extra is repeated"},
+		{form: url.Values{"oauth_nonce": {"x"}}, err: "oauth_nonce is repeated"},
+		{form: url.Values{"oauth_verifier": {"x"}}},
+		{form: url.Values{"oauth_verifier": {"x"}}, r: request{verifier: "v"}, err: "oauth_verifier is repeated"},
+		{r: request{verifier: "1234\n"}, err: "oauth_verifier contains a newline"},
+	} {
+		c := Client{StrictMode: &StrictMode{}, OAuthParamPolicy: OAuthParamAllow}
+		r := tt.r
+		r.method = "POST"
+		r.u = u
+		r.form = tt.form
+		r.credentials = &Credentials{}
+		_, err := c.oauthParams(&r)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("form %q returned error %v", tt.form, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("form %q returned error %v, want %q", tt.form, err, tt.err)
+		}
+	}
+
+	c := Client{StrictMode: &StrictMode{MaxValueLength: 3}}
+	u, _ = url.Parse("https://example.com/api")
+	if _, err := c.oauthParams(&request{method: "GET", u: u, form: url.Values{"q": {"abc"}}}); err != nil {
+		t.Errorf("value with MaxValueLength bytes returned error %v", err)
+	}
+	if _, err := c.oauthParams(&request{method: "GET", u: u, form: url.Values{"q": {"abcd"}}}); err == nil {
+		t.Error("value longer than MaxValueLength returned nil error")
+	}
+}
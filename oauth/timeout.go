@@ -0,0 +1,96 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"io"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// DefaultCredentialsTimeout is the default time limit for temporary
+// credential and token credential requests.
+const DefaultCredentialsTimeout = 10 * time.Second
+
+// Timeouts specifies time limits for the requests sent by the Client
+// methods. A limit is used only when the context passed to the method does
+// not have a deadline. Limits require Go 1.7 or later.
+type Timeouts struct {
+	// TemporaryCredentials is the limit for temporary credential requests.
+	// If this field is zero, then DefaultCredentialsTimeout is used. If this
+	// field is negative, then there is no limit.
+	TemporaryCredentials time.Duration
+
+	// TokenCredentials is the limit for requests that get, renew, verify or
+	// revoke token credentials. If this field is zero, then
+	// DefaultCredentialsTimeout is used. If this field is negative, then
+	// there is no limit.
+	TokenCredentials time.Duration
+
+	// API is the limit for requests sent by the Get, Head, Post, Put and
+	// Delete methods, including the time to read the response body. The
+	// limit ends when the response body is closed. If this field is zero or
+	// negative, then there is no limit.
+	API time.Duration
+}
+
+// timeout returns the limit for operation op or zero if there is no limit.
+func (t *Timeouts) timeout(op string) time.Duration {
+	var d time.Duration
+	switch op {
+	case "request_token":
+		d = t.TemporaryCredentials
+		if d == 0 {
+			d = DefaultCredentialsTimeout
+		}
+	case "access_token", "renew_token", "validate_token", "revoke_token":
+		d = t.TokenCredentials
+		if d == 0 {
+			d = DefaultCredentialsTimeout
+		}
+	case "api_call":
+		d = t.API
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// withTimeout returns a context with the limit for operation op if ctx does
+// not have a deadline.
+func (c *Client) withTimeout(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	d := c.Timeouts.timeout(op)
+	if d == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// cancelBody is a response body that cancels the request context on close.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.7
+// +build go1.7
+
+package oauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestTimeouts(t *testing.T) {
+	var deadline time.Duration
+	d := doerFunc(func(req *http.Request) (*http.Response, error) {
+		deadline = 0
+		if dl, ok := req.Context().Deadline(); ok {
+			deadline = dl.Sub(time.Now())
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader("oauth_token=t&oauth_token_secret=s")),
+			Request:    req,
+		}, nil
+	})
+	ctx := context.WithValue(context.Background(), HTTPClient, d)
+	c := Client{
+		TemporaryCredentialRequestURI: "https://example.com/request",
+		TokenRequestURI:               "https://example.com/access",
+	}
+
+	within := func(name string, want time.Duration) {
+		if deadline > want || deadline <= want-time.Second {
+			t.Errorf("%s: deadline in %v, want %v", name, deadline, want)
+		}
+	}
+
+	if _, err := c.RequestTemporaryCredentialsContext(ctx, "oob", nil); err != nil {
+		t.Fatal(err)
+	}
+	within("default temporary credentials", DefaultCredentialsTimeout)
+
+	c.Timeouts = Timeouts{TemporaryCredentials: -1, TokenCredentials: 3 * time.Second, API: 5 * time.Second}
+	if _, err := c.RequestTemporaryCredentialsContext(ctx, "oob", nil); err != nil {
+		t.Fatal(err)
+	}
+	if deadline != 0 {
+		t.Errorf("negative temporary credentials timeout set deadline in %v", deadline)
+	}
+	if _, _, err := c.RequestTokenContext(ctx, &Credentials{}, "verifier"); err != nil {
+		t.Fatal(err)
+	}
+	within("token credentials", 3*time.Second)
+
+	resp, err := c.GetContext(ctx, &Credentials{}, "https://example.com/api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	within("api", 5*time.Second)
+	if _, err := ioutil.ReadAll(resp.Body); err != nil {
+		t.Errorf("reading API response body returned error %v", err)
+	}
+	resp.Body.Close()
+
+	// A deadline in the context is not changed.
+	ctx2, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if _, err := c.RequestTemporaryCredentialsContext(ctx2, "oob", nil); err != nil {
+		t.Fatal(err)
+	}
+	within("context deadline", time.Minute)
+}
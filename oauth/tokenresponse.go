@@ -0,0 +1,111 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// isJSONMediaType returns true if the Content-Type header specifies JSON.
+func isJSONMediaType(header http.Header) bool {
+	mt, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// parseCredentialsResponse parses the body of a credentials response. The
+// body is form encoded as required by the RFC or, for providers that do not
+// follow the RFC, a JSON object when the Content-Type header specifies JSON.
+// Strings, numbers and booleans in a JSON object are converted to parameter
+// values. Other JSON values are stored as JSON text.
+func parseCredentialsResponse(header http.Header, p []byte) (url.Values, error) {
+	if !isJSONMediaType(header) {
+		return url.ParseQuery(string(p))
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(p, &obj); err != nil {
+		return nil, errors.New("oauth: cannot parse JSON credentials response: " + err.Error())
+	}
+	m := make(url.Values, len(obj))
+	for k, raw := range obj {
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return nil, errors.New("oauth: cannot parse JSON credentials response: " + err.Error())
+		}
+		switch v := v.(type) {
+		case string:
+			m.Set(k, v)
+		case json.Number:
+			m.Set(k, v.String())
+		case bool:
+			if v {
+				m.Set(k, "true")
+			} else {
+				m.Set(k, "false")
+			}
+		case nil:
+		default:
+			m.Set(k, string(raw))
+		}
+	}
+	return m, nil
+}
+
+// contentTypeNote returns a description of the response Content-Type for
+// error messages or "" if the type is expected for a credentials response.
+func contentTypeNote(header http.Header) string {
+	ct := header.Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(ct)
+	switch mt {
+	case "", "application/x-www-form-urlencoded", "text/plain":
+		return ""
+	}
+	return " (response content type " + ct + ")"
+}
+
+// utf8BOM is the UTF-8 encoding of the byte order mark.
+const utf8BOM = "\xef\xbb\xbf"
+
+// cleanCredentialsResponse returns the body of a credentials response
+// without a byte order mark, surrounding white space and HTML escaping of
+// the parameter separator.
+func cleanCredentialsResponse(p []byte) []byte {
+	p = bytes.TrimPrefix(p, []byte(utf8BOM))
+	p = bytes.TrimSpace(p)
+	return bytes.Replace(p, []byte("&amp;"), []byte("&"), -1)
+}
+
+// maxBodyNote is the maximum number of body bytes shown in error messages.
+const maxBodyNote = 200
+
+// bodyNote returns the raw response body for error messages. The body is not
+// shown if it contains a token secret.
+func bodyNote(p []byte) string {
+	if bytes.Contains(p, []byte("oauth_token_secret")) {
+		return ", body contains oauth_token_secret and is not shown"
+	}
+	if len(p) > maxBodyNote {
+		return ", body " + strconv.Quote(string(p[:maxBodyNote])) + "..."
+	}
+	return ", body " + strconv.Quote(string(p))
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"container/list"
+	"net/url"
+	"sync"
+)
+
+// DefaultURICacheSize is the size of a URICache created with a size of zero.
+const DefaultURICacheSize = 256
+
+// URICache is a least recently used cache of encoded signature base string
+// URIs. Set the Client URICache field to a cache to avoid normalizing and
+// encoding the URI of an endpoint each time a request to the endpoint is
+// signed. The query string is not part of the base string URI; requests to
+// one endpoint with different parameters share a cache entry.
+//
+// A URICache is safe for concurrent use. Clients with different values for
+// the DefaultPorts field must not share a cache. The zero value is an empty
+// cache with size DefaultURICacheSize.
+type URICache struct {
+	size int
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[uriKey]*list.Element
+}
+
+// uriKey is the cache key for a URL. The key includes the Client settings
+// that change the base string URI, except for DefaultPorts.
+type uriKey struct {
+	scheme, opaque, host, path, rawPath string
+	unescaped, escaped                  string
+	unescapedPath, retainDefaultPort    bool
+}
+
+type uriEntry struct {
+	key     uriKey
+	encoded []byte
+}
+
+// NewURICache returns a cache that holds up to size URIs. If size is zero,
+// then DefaultURICacheSize is used.
+func NewURICache(size int) *URICache {
+	if size <= 0 {
+		size = DefaultURICacheSize
+	}
+	return &URICache{size: size, ll: list.New(), entries: make(map[uriKey]*list.Element)}
+}
+
+// Len returns the number of URIs in the cache.
+func (uc *URICache) Len() int {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.ll == nil {
+		return 0
+	}
+	return uc.ll.Len()
+}
+
+// init initializes a zero value cache. The caller must hold uc.mu.
+func (uc *URICache) init() {
+	if uc.ll != nil {
+		return
+	}
+	if uc.size <= 0 {
+		uc.size = DefaultURICacheSize
+	}
+	uc.ll = list.New()
+	uc.entries = make(map[uriKey]*list.Element)
+}
+
+// encodedBaseStringURI returns the encoded base string URI for u. The caller
+// must not modify the returned slice.
+func (c *Client) encodedBaseStringURI(u *url.URL, noEscape *[256]bool) []byte {
+	uc := c.URICache
+	if uc == nil {
+		return encodeTable(c.baseStringURI(u), false, noEscape)
+	}
+	key := uriKey{
+		scheme:            u.Scheme,
+		opaque:            u.Opaque,
+		host:              u.Host,
+		path:              u.Path,
+		rawPath:           u.RawPath,
+		unescapedPath:     c.UnescapedPath,
+		retainDefaultPort: c.RetainDefaultPort,
+	}
+	if c.Compatibility != nil {
+		key.unescaped = c.Compatibility.Unescaped
+		key.escaped = c.Compatibility.Escaped
+	}
+
+	uc.mu.Lock()
+	uc.init()
+	if e, ok := uc.entries[key]; ok {
+		uc.ll.MoveToFront(e)
+		uc.mu.Unlock()
+		return e.Value.(*uriEntry).encoded
+	}
+	uc.mu.Unlock()
+
+	encoded := encodeTable(c.baseStringURI(u), false, noEscape)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if e, ok := uc.entries[key]; ok {
+		// Another goroutine added the URI.
+		uc.ll.MoveToFront(e)
+		return encoded
+	}
+	uc.entries[key] = uc.ll.PushFront(&uriEntry{key: key, encoded: encoded})
+	for uc.ll.Len() > uc.size {
+		e := uc.ll.Back()
+		uc.ll.Remove(e)
+		delete(uc.entries, e.Value.(*uriEntry).key)
+	}
+	return encoded
+}
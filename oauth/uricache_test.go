@@ -0,0 +1,82 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+func TestURICache(t *testing.T) {
+	urls := []string{
+		"https://Example.com:443/a%2Fb",
+		"https://example.com/a/b",
+		"https://example.com/a/b?x=1",
+		"http://example.com:8080/ü",
+		"https://example.com/a/b",
+	}
+	var plain Client
+	for _, cached := range []*Client{
+		{URICache: NewURICache(0)},
+		{URICache: NewURICache(2)},
+		{URICache: &URICache{}},
+	} {
+		for i := 0; i < 2; i++ {
+			for _, urlStr := range urls {
+				u, _ := url.Parse(urlStr)
+				var got, want bytes.Buffer
+				cached.writeBaseString(&got, "GET", u, nil, nil)
+				plain.writeBaseString(&want, "GET", u, nil, nil)
+				if got.String() != want.String() {
+					t.Errorf("base string with cache for %s = %q, want %q", urlStr, got.String(), want.String())
+				}
+			}
+		}
+		if n := cached.URICache.Len(); n > cached.URICache.size {
+			t.Errorf("cache size %d holds %d URIs", cached.URICache.size, n)
+		}
+	}
+
+	// Settings that change the base string URI are part of the key.
+	c := Client{URICache: NewURICache(0)}
+	u, _ := url.Parse("https://example.com:443/a%2Fb")
+	first := string(c.encodedBaseStringURI(u, c.Compatibility.noEscape()))
+	c.RetainDefaultPort = true
+	c.UnescapedPath = true
+	if got := string(c.encodedBaseStringURI(u, c.Compatibility.noEscape())); got == first {
+		t.Errorf("cached URI %q not changed by settings", got)
+	}
+	if n := c.URICache.Len(); n != 2 {
+		t.Errorf("cache holds %d URIs, want 2", n)
+	}
+
+	// Compatibility settings are part of the key, and equal settings share
+	// an entry.
+	u, _ = url.Parse("https://example.com/a*b")
+	for i := 0; i < 2; i++ {
+		c.Compatibility = &Compatibility{Unescaped: "*"}
+		if got := string(c.encodedBaseStringURI(u, c.Compatibility.noEscape())); got != "https%3A%2F%2Fexample.com%2Fa*b" {
+			t.Errorf("cached URI with Compatibility = %q", got)
+		}
+	}
+	c.Compatibility = nil
+	if got := string(c.encodedBaseStringURI(u, c.Compatibility.noEscape())); got != "https%3A%2F%2Fexample.com%2Fa%2Ab" {
+		t.Errorf("cached URI without Compatibility = %q", got)
+	}
+	if n := c.URICache.Len(); n != 4 {
+		t.Errorf("cache holds %d URIs, want 4", n)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Validate checks the endpoint URLs in the client configuration. Call
+// Validate when the client is configured to report errors before the first
+// request is signed. The endpoint URLs must be absolute URLs with the http or
+// https scheme and without a fragment. If RequireClientCertificate is set,
+// then the https scheme is required. Empty endpoint URLs are not checked.
+// Validate also checks the Compatibility settings.
+func (c *Client) Validate() error {
+	endpoints := []struct {
+		name  string
+		value string
+	}{
+		{"TemporaryCredentialRequestURI", c.TemporaryCredentialRequestURI},
+		{"ResourceOwnerAuthorizationURI", c.ResourceOwnerAuthorizationURI},
+		{"TokenRequestURI", c.TokenRequestURI},
+		{"RenewCredentialRequestURI", c.RenewCredentialRequestURI},
+		{"RevokeTokenURI", c.RevokeTokenURI},
+	}
+	for _, e := range endpoints {
+		if e.value == "" {
+			continue
+		}
+		if err := c.validateEndpoint(e.value); err != nil {
+			return errors.New("oauth: " + e.name + " " + err.Error())
+		}
+	}
+	if c.SignatureMethod == RSASHA1 && c.PrivateKey == nil {
+		return errors.New("oauth: private key not set")
+	}
+	return c.Compatibility.check()
+}
+
+func (c *Client) validateEndpoint(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !u.IsAbs() || u.Host == "":
+		return errors.New("is not an absolute URL")
+	case strings.Contains(s, "#"):
+		return errors.New("has a fragment")
+	case u.Scheme == "https":
+	case u.Scheme == "http" && !c.RequireClientCertificate:
+	default:
+		return errors.New("has unsupported scheme " + u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauth
+
+import "testing"
+
+var validateTests = []struct {
+	c  Client
+	ok bool
+}{
+	{Client{}, true},
+	{Client{TemporaryCredentialRequestURI: "https://example.com/request", TokenRequestURI: "http://example.com/access"}, true},
+	{Client{TokenRequestURI: "/access"}, false},
+	{Client{TokenRequestURI: "example.com/access"}, false},
+	{Client{ResourceOwnerAuthorizationURI: "https://example.com/authorize#x"}, false},
+	{Client{ResourceOwnerAuthorizationURI: "https://example.com/authorize#"}, false},
+	{Client{RevokeTokenURI: "ftp://example.com/revoke"}, false},
+	{Client{RenewCredentialRequestURI: "http://%zz"}, false},
+	{Client{TokenRequestURI: "http://example.com/access", RequireClientCertificate: true}, false},
+	{Client{TokenRequestURI: "https://example.com/access", RequireClientCertificate: true}, true},
+	{Client{SignatureMethod: RSASHA1}, false},
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range validateTests {
+		err := tt.c.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate() for %+v returned %v, want ok=%v", tt.c, err, tt.ok)
+		}
+	}
+}
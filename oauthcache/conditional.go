@@ -0,0 +1,191 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// ErrNotModified is returned by Conditional.Get when the server responds
+// with status 304.
+var ErrNotModified = errors.New("oauthcache: not modified")
+
+// Validators are the response headers used to validate a resource.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+func responseValidators(h http.Header) Validators {
+	return Validators{ETag: h.Get("Etag"), LastModified: h.Get("Last-Modified")}
+}
+
+// requestHeader returns the conditional request headers for the validators
+// or nil if there are no validators.
+func (v Validators) requestHeader() http.Header {
+	if v == (Validators{}) {
+		return nil
+	}
+	h := make(http.Header)
+	if v.ETag != "" {
+		h.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		h.Set("If-Modified-Since", v.LastModified)
+	}
+	return h
+}
+
+// ValidatorStore stores validators by key. Keys are computed with the Key
+// function.
+type ValidatorStore interface {
+	// Get returns the validators for key. Get returns the zero value if
+	// there are no validators for the key.
+	Get(key string) (Validators, error)
+
+	// Put stores the validators for key.
+	Put(key string, v Validators) error
+}
+
+// MemoryValidatorStore is a ValidatorStore that stores validators in memory.
+// The zero value is ready to use.
+type MemoryValidatorStore struct {
+	// MaxEntries is the maximum number of stored validators. When the store
+	// is full, the validators that were put first are removed. If this field
+	// is zero, then DefaultMaxEntries is used.
+	MaxEntries int
+
+	mu  sync.Mutex
+	m   map[string]storedValidators
+	seq uint64
+}
+
+type storedValidators struct {
+	v   Validators
+	seq uint64
+}
+
+func (s *MemoryValidatorStore) maxEntries() int {
+	if s.MaxEntries > 0 {
+		return s.MaxEntries
+	}
+	return DefaultMaxEntries
+}
+
+// Get implements the ValidatorStore interface.
+func (s *MemoryValidatorStore) Get(key string) (Validators, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m[key].v, nil
+}
+
+// Put implements the ValidatorStore interface.
+func (s *MemoryValidatorStore) Put(key string, v Validators) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[string]storedValidators)
+	}
+	if _, ok := s.m[key]; !ok {
+		for len(s.m) >= s.maxEntries() {
+			first := ""
+			for k, sv := range s.m {
+				if first == "" || sv.seq < s.m[first].seq {
+					first = k
+				}
+			}
+			delete(s.m, first)
+		}
+	}
+	s.seq++
+	s.m[key] = storedValidators{v: v, seq: s.seq}
+	return nil
+}
+
+// Len returns the number of stored validators.
+func (s *MemoryValidatorStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.m)
+}
+
+// Conditional sends conditional signed GET requests. The validators from a
+// response are saved and sent in the If-None-Match and If-Modified-Since
+// headers of the next request for the same resource.
+type Conditional struct {
+	// Client signs the requests.
+	Client *oauth.Client
+
+	// Store stores the validators. If this field is nil, then the
+	// validators are stored in memory.
+	Store ValidatorStore
+
+	memoryStore MemoryValidatorStore
+}
+
+func (c *Conditional) store() ValidatorStore {
+	if c.Store != nil {
+		return c.Store
+	}
+	return &c.memoryStore
+}
+
+// Get issues a conditional GET using the Client GetContext method. If the
+// server responds with status 304, then Get closes the response body and
+// returns the response with ErrNotModified.
+func (c *Conditional) Get(ctx context.Context, credentials *oauth.Credentials, urlStr string, form url.Values) (*http.Response, error) {
+	if credentials == nil {
+		credentials = oauth.CredentialsFromContext(ctx)
+	}
+	key, err := Key(c.Client, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.store().Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.Client
+	if h := v.requestHeader(); h != nil {
+		c2 := *c.Client
+		c2.Header = mergeHeader(c.Client.Header, h)
+		client = &c2
+	}
+
+	resp, err := client.GetContext(ctx, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		resp.Body.Close()
+		return resp, ErrNotModified
+	case http.StatusOK:
+		if v := responseValidators(resp.Header); v != (Validators{}) {
+			if err := c.store().Put(key, v); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		}
+	}
+	return resp, nil
+}
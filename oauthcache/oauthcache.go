@@ -0,0 +1,266 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package oauthcache caches responses to signed GET requests.
+//
+// Responses are cached by the request URL, parameters, consumer key and
+// token. The signature, nonce and timestamp are not part of the key because
+// these change with every request. When a cached response expires, the cache
+// validates the response with the server using the ETag and Last-Modified
+// response headers if present.
+//
+// The Conditional type sends conditional requests without caching the
+// response body. The application handles ErrNotModified by using its own copy
+// of the resource.
+package oauthcache // import "github.com/garyburd/go-oauth/oauthcache"
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// DefaultTTL is the default time that a response is used without validation.
+const DefaultTTL = time.Minute
+
+// DefaultMaxEntries is the default maximum number of cached responses.
+const DefaultMaxEntries = 1000
+
+// Cache is a read-through cache for signed GET requests.
+type Cache struct {
+	// Client signs the requests.
+	Client *oauth.Client
+
+	// TTL is the time that a cached response is used without validation. If
+	// this field is zero, then DefaultTTL is used.
+	TTL time.Duration
+
+	// MaxBodySize is the maximum size of a cached response body. Larger
+	// responses are not cached. If this field is zero, then
+	// oauth.DefaultMaxResponseSize is used.
+	MaxBodySize int64
+
+	// MaxEntries is the maximum number of cached responses. When the cache
+	// is full, expired responses are removed and then the response that
+	// expires first. If this field is zero, then DefaultMaxEntries is used.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is a cached response. Entries are not modified after they are added
+// to the cache.
+type entry struct {
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+func (c *Cache) ttl() time.Duration {
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return DefaultTTL
+}
+
+func (c *Cache) maxEntries() int {
+	if c.MaxEntries > 0 {
+		return c.MaxEntries
+	}
+	return DefaultMaxEntries
+}
+
+func (c *Cache) maxBodySize() int64 {
+	if c.MaxBodySize > 0 {
+		return c.MaxBodySize
+	}
+	return oauth.DefaultMaxResponseSize
+}
+
+// Key returns the cache key for a GET request. The key is a hash of the
+// lowercase scheme and host, the path of the URL, the query and form
+// parameters, the consumer key and the token. The parameters are not
+// filtered or normalized by the Client, so requests that differ in any
+// parameter have different keys.
+func Key(c *oauth.Client, credentials *oauth.Credentials, urlStr string, form url.Values) (string, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return "", err
+	}
+	params := u.Query()
+	for k, vs := range form {
+		params[k] = append(params[k], vs...)
+	}
+	h := sha256.New()
+	io.WriteString(h, http.MethodGet)
+	io.WriteString(h, "\x00")
+	io.WriteString(h, strings.ToLower(u.Scheme+"://"+u.Host)+u.EscapedPath())
+	io.WriteString(h, "\x00")
+	io.WriteString(h, params.Encode())
+	io.WriteString(h, "\x00")
+	io.WriteString(h, c.Credentials.Token)
+	io.WriteString(h, "\x00")
+	if credentials != nil {
+		io.WriteString(h, credentials.Token)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get issues a GET using the Client GetContext method or returns a cached
+// response. Only responses with status 200 are cached. Responses with the
+// Cache-Control no-store directive are not cached.
+func (c *Cache) Get(ctx context.Context, credentials *oauth.Credentials, urlStr string, form url.Values) (*http.Response, error) {
+	if credentials == nil {
+		credentials = oauth.CredentialsFromContext(ctx)
+	}
+	key, err := Key(c.Client, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	e := c.entries[key]
+	c.mu.Unlock()
+
+	client := c.Client
+	if e != nil {
+		if timeNow().Before(e.expires) {
+			return e.response(), nil
+		}
+		if h := responseValidators(e.header).requestHeader(); h != nil {
+			c2 := *c.Client
+			c2.Header = mergeHeader(c.Client.Header, h)
+			client = &c2
+		}
+	}
+
+	resp, err := client.GetContext(ctx, credentials, urlStr, form)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && e != nil:
+		resp.Body.Close()
+		e = &entry{header: e.header, body: e.body, expires: timeNow().Add(c.ttl())}
+		c.add(key, e)
+		return e.response(), nil
+	case resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store"):
+		return resp, nil
+	}
+
+	max := c.maxBodySize()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > max {
+		// Too large to cache. Return the response with the full body.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.add(key, &entry{header: resp.Header.Clone(), body: body, expires: timeNow().Add(c.ttl())})
+	return resp, nil
+}
+
+// add adds an entry to the cache. If the cache is full, then expired entries
+// are removed and then the entry that expires first.
+func (c *Cache) add(key string, e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*entry)
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries() {
+		now := timeNow()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for len(c.entries) >= c.maxEntries() {
+			first := ""
+			for k, e := range c.entries {
+				if first == "" || e.expires.Before(c.entries[first].expires) {
+					first = k
+				}
+			}
+			delete(c.entries, first)
+		}
+	}
+	c.entries[key] = e
+}
+
+// Len returns the number of cached responses.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Delete removes the cached response for a request.
+func (c *Cache) Delete(credentials *oauth.Credentials, urlStr string, form url.Values) {
+	key, err := Key(c.Client, credentials, urlStr, form)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+func (e *entry) response() *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+	}
+}
+
+func mergeHeader(a, b http.Header) http.Header {
+	h := a.Clone()
+	if h == nil {
+		h = make(http.Header)
+	}
+	for k, v := range b {
+		h[k] = v
+	}
+	return h
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcache
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestKey(t *testing.T) {
+	c := &oauth.Client{Credentials: oauth.Credentials{Token: "key"}}
+	cred := &oauth.Credentials{Token: "token", Secret: "a"}
+	k1, _ := Key(c, cred, "http://Example.com/a?y=2&x=1", nil)
+	k2, _ := Key(c, &oauth.Credentials{Token: "token", Secret: "b"}, "http://example.com/a?x=1", url.Values{"y": {"2"}})
+	if k1 != k2 {
+		t.Errorf("keys for equivalent requests differ")
+	}
+	k3, _ := Key(c, &oauth.Credentials{Token: "other"}, "http://example.com/a?x=1&y=2", nil)
+	if k1 == k3 {
+		t.Errorf("keys for different tokens are equal")
+	}
+
+	c.ExcludeParams = []string{"page"}
+	k4, _ := Key(c, cred, "http://example.com/a?page=1", nil)
+	k5, _ := Key(c, cred, "http://example.com/a?page=2", nil)
+	k6, _ := Key(c, cred, "http://example.com/a", url.Values{"page": {"2"}})
+	if k4 == k5 || k5 != k6 {
+		t.Errorf("keys for excluded params are not distinct")
+	}
+}
+
+func TestCache_ExcludeParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "page "+r.FormValue("page"))
+	}))
+	defer ts.Close()
+
+	c := &Cache{Client: &oauth.Client{ExcludeParams: []string{"page"}}}
+	for _, page := range []string{"1", "2", "1"} {
+		resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL+"?page="+page, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if want := "page " + page; string(p) != want {
+			t.Errorf("body %q, want %q", p, want)
+		}
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	hits, notModified := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		io.WriteString(w, "hello")
+	}))
+	defer ts.Close()
+
+	c := &Cache{Client: &oauth.Client{}, TTL: time.Minute}
+	get := func() string {
+		resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		p, _ := ioutil.ReadAll(resp.Body)
+		return string(p)
+	}
+
+	for i := 0; i < 2; i++ {
+		if body := get(); body != "hello" {
+			t.Errorf("body %q, want hello", body)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server hits %d, want 1", hits)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if body := get(); body != "hello" {
+		t.Errorf("body %q after validation, want hello", body)
+	}
+	if hits != 2 || notModified != 1 {
+		t.Errorf("server hits %d, not modified %d, want 2, 1", hits, notModified)
+	}
+
+	c.Delete(&oauth.Credentials{Token: "token"}, ts.URL, nil)
+	get()
+	if hits != 3 || notModified != 1 {
+		t.Errorf("server hits %d, not modified %d after delete, want 3, 1", hits, notModified)
+	}
+}
+
+func TestCache_MaxEntries(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	c := &Cache{Client: &oauth.Client{}, TTL: time.Minute, MaxEntries: 2}
+	get := func(path string) {
+		resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	get("/a")
+	now = now.Add(time.Second)
+	get("/b")
+	get("/c")
+	if n := c.Len(); n != 2 {
+		t.Errorf("cache holds %d responses, want 2", n)
+	}
+	key, _ := Key(c.Client, &oauth.Credentials{Token: "token"}, ts.URL+"/a", nil)
+	if c.entries[key] != nil {
+		t.Error("response that expires first was not removed")
+	}
+
+	// Expired responses are removed when the cache is full.
+	now = now.Add(2 * time.Minute)
+	get("/d")
+	if n := c.Len(); n != 1 {
+		t.Errorf("cache holds %d responses after expiry, want 1", n)
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		io.WriteString(w, "hello")
+	}))
+	defer ts.Close()
+
+	c := &Cache{Client: &oauth.Client{}, TTL: time.Nanosecond}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL, nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConditional(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		io.WriteString(w, "hello")
+	}))
+	defer ts.Close()
+
+	c := &Conditional{Client: &oauth.Client{}}
+	resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	resp, err = c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL, nil)
+	if err != ErrNotModified {
+		t.Errorf("error %v, want %v", err, ErrNotModified)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotModified {
+		t.Errorf("response %v, want status 304", resp)
+	}
+
+	resp, err = c.Get(context.Background(), &oauth.Credentials{Token: "other"}, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("request with other token returned error %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestConditional_ExcludeParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + r.FormValue("page") + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", etag)
+		io.WriteString(w, "hello")
+	}))
+	defer ts.Close()
+
+	c := &Conditional{Client: &oauth.Client{ExcludeParams: []string{"page"}}}
+	for _, page := range []string{"1", "2"} {
+		resp, err := c.Get(context.Background(), &oauth.Credentials{Token: "token"}, ts.URL+"?page="+page, nil)
+		if err != nil {
+			t.Fatalf("page %s returned error %v", page, err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestMemoryValidatorStore_MaxEntries(t *testing.T) {
+	s := &MemoryValidatorStore{MaxEntries: 2}
+	s.Put("a", Validators{ETag: "a"})
+	s.Put("b", Validators{ETag: "b"})
+	s.Put("a", Validators{ETag: "a2"})
+	s.Put("c", Validators{ETag: "c"})
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+	for key, want := range map[string]string{"a": "a2", "b": "", "c": "c"} {
+		if v, _ := s.Get(key); v.ETag != want {
+			t.Errorf("Get(%s).ETag = %q, want %q", key, v.ETag, want)
+		}
+	}
+}
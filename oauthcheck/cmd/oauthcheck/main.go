@@ -0,0 +1,95 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Command oauthcheck reports the signature quirks of a provider.
+//
+// Usage:
+//
+//	oauthcheck -config config.json https://provider.example.com/oauth/request_token
+//
+// The configuration file contains the application's credentials in the form
+// {"Token": "consumer key", "Secret": "consumer secret"}.
+//
+// The -health flag sends a signed GET request to an API endpoint and reports
+// DNS, TLS, HTTP status and OAuth problem diagnostics as JSON:
+//
+//	oauthcheck -config config.json -health https://provider.example.com/api/status
+//
+// The command exits with status 1 if the check fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/garyburd/go-oauth/oauth"
+	"github.com/garyburd/go-oauth/oauthcheck"
+)
+
+var (
+	credPath = flag.String("config", "config.json", "Path to configuration file containing the application's credentials.")
+	method   = flag.String("method", "POST", "HTTP method for the temporary credentials request.")
+	health   = flag.String("health", "", "URL for a signed health check request. The request token URL is not used.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: oauthcheck [flags] request-token-url\n       oauthcheck [flags] -health url\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if (*health == "" && flag.NArg() != 1) || (*health != "" && flag.NArg() != 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := &oauth.Client{
+		TemporaryCredentialRequestURI: flag.Arg(0),
+		TemporaryCredentialsMethod:    *method,
+	}
+	b, err := ioutil.ReadFile(*credPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &c.Credentials); err != nil {
+		log.Fatal(err)
+	}
+
+	if *health != "" {
+		h := oauthcheck.CheckHealth(context.Background(), c, nil, *health)
+		p, err := json.MarshalIndent(h, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s\n", p)
+		if !h.OK() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	r, err := oauthcheck.Check(context.Background(), c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r.WriteTo(os.Stdout)
+	if len(r.Quirks()) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// Stages of a health check request.
+const (
+	StageDNS     = "dns"
+	StageConnect = "connect"
+	StageTLS     = "tls"
+	StageHTTP    = "http"
+	StageStatus  = "status"
+)
+
+// Health is the result of a health check request.
+type Health struct {
+	// URL is the request URL.
+	URL string
+
+	// Addrs are the addresses returned by the DNS lookup.
+	Addrs []string `json:",omitempty"`
+
+	// DNS, Connect and TLS are the durations of the DNS lookup, connection
+	// and TLS handshake. The durations are zero when the step is skipped,
+	// for example when a connection is reused.
+	DNS     time.Duration `json:",omitempty"`
+	Connect time.Duration `json:",omitempty"`
+	TLS     time.Duration `json:",omitempty"`
+
+	// TLSVersion is the negotiated TLS version.
+	TLSVersion string `json:",omitempty"`
+
+	// StatusCode is the HTTP response status code or zero if no response
+	// was received.
+	StatusCode int `json:",omitempty"`
+
+	// Problem is the oauth_problem reported in the response.
+	Problem oauth.Problem `json:",omitempty"`
+
+	// Duration is the total duration of the request.
+	Duration time.Duration
+
+	// Stage is the stage that failed: StageDNS, StageConnect, StageTLS,
+	// StageHTTP or StageStatus. Stage is empty if the check succeeded.
+	Stage string `json:",omitempty"`
+
+	// Error describes the failure.
+	Error string `json:",omitempty"`
+}
+
+// OK returns true if the check succeeded.
+func (h *Health) OK() bool {
+	return h.Stage == ""
+}
+
+// WriteTo writes the result in text form to w.
+func (h *Health) WriteTo(w io.Writer) (int64, error) {
+	status := "ok"
+	if !h.OK() {
+		status = "FAIL " + h.Stage + ": " + h.Error
+	}
+	n, err := fmt.Fprintf(w, "%s %s\naddrs=%v dns=%v connect=%v tls=%v %s status=%d problem=%q total=%v\n",
+		h.URL, status, h.Addrs, h.DNS, h.Connect, h.TLS, h.TLSVersion, h.StatusCode, h.Problem, h.Duration)
+	return int64(n), err
+}
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS1.0",
+	tls.VersionTLS11: "TLS1.1",
+	tls.VersionTLS12: "TLS1.2",
+	tls.VersionTLS13: "TLS1.3",
+}
+
+// CheckHealth sends a signed GET request to urlStr and reports the result.
+// The credentials are the token credentials or nil for a request signed with
+// the client credentials only. A response with a status other than 2xx is a
+// failure. Use CheckHealth in readiness probes for services that depend on
+// the provider. The HTTP client is taken from the context as described in
+// the oauth package documentation. Timing information is only available when
+// the HTTP client is an *http.Client.
+func CheckHealth(ctx context.Context, c *oauth.Client, credentials *oauth.Credentials, urlStr string) *Health {
+	h := &Health{URL: urlStr}
+	var mu sync.Mutex
+	var dnsStart, connectStart, tlsStart time.Time
+	stage := StageHTTP
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			stage = StageDNS
+			dnsStart = time.Now()
+			mu.Unlock()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			h.DNS = time.Since(dnsStart)
+			for _, a := range info.Addrs {
+				h.Addrs = append(h.Addrs, a.String())
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			defer mu.Unlock()
+			stage = StageConnect
+			if connectStart.IsZero() {
+				connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				h.Connect = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			stage = StageTLS
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				h.TLS = time.Since(tlsStart)
+				h.TLSVersion = tlsVersions[state.Version]
+			}
+		},
+		GotConn: func(httptrace.GotConnInfo) {
+			mu.Lock()
+			stage = StageHTTP
+			mu.Unlock()
+		},
+	}
+
+	start := time.Now()
+	resp, err := c.GetContext(httptrace.WithClientTrace(ctx, trace), credentials, urlStr, nil)
+	var body []byte
+	if err == nil {
+		body, err = ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+	}
+	h.Duration = time.Since(start)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil {
+		h.Stage = stage
+		h.Error = err.Error()
+		return h
+	}
+	h.StatusCode = resp.StatusCode
+	h.Problem = oauth.ResponseProblem(resp.Header, body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		h.Stage = StageStatus
+		h.Error = fmt.Sprintf("status %d", resp.StatusCode)
+		if h.Problem != "" {
+			h.Error += ", oauth_problem=" + string(h.Problem)
+		}
+	}
+	return h
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcheck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestCheckHealth(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/expired" {
+			w.Header().Set("WWW-Authenticate", `OAuth oauth_problem="token_expired"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") {
+			t.Errorf("request not signed")
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	ctx := context.WithValue(context.Background(), oauth.HTTPClient, ts.Client())
+	c := &oauth.Client{}
+
+	h := CheckHealth(ctx, c, nil, ts.URL+"/status")
+	if !h.OK() || h.StatusCode != http.StatusOK || h.TLSVersion == "" {
+		t.Errorf("CheckHealth(status) = %+v, want OK with TLS version", h)
+	}
+
+	h = CheckHealth(ctx, c, &oauth.Credentials{Token: "token"}, ts.URL+"/expired")
+	if h.Stage != StageStatus || h.Problem != "token_expired" {
+		t.Errorf("CheckHealth(expired) = %+v, want status failure with token_expired", h)
+	}
+	var buf bytes.Buffer
+	h.WriteTo(&buf)
+	if !strings.Contains(buf.String(), "oauth_problem=token_expired") {
+		t.Errorf("WriteTo() = %q, want problem", buf.String())
+	}
+
+	ts.Close()
+	h = CheckHealth(ctx, c, nil, ts.URL+"/status")
+	if h.OK() || h.Stage != StageConnect {
+		t.Errorf("CheckHealth(closed) = %+v, want connect failure", h)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package oauthcheck finds the signature quirks of a provider.
+//
+// Check sends a series of signed temporary credential requests to the
+// provider. Each request includes parameters that are encoded differently by
+// providers that do not follow the specification. The report lists the
+// requests that the provider rejected and recommends Client settings that work
+// around the quirks.
+//
+// Check requests temporary credentials from the provider. Run it against a
+// test application if the provider limits the number of requests.
+//
+// CheckHealth sends a signed request to an API endpoint and reports
+// diagnostics for readiness probes.
+package oauthcheck // import "github.com/garyburd/go-oauth/oauthcheck"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// Result is the result of one request.
+type Result struct {
+	// Name describes the request.
+	Name string
+
+	// Err is the error returned by the provider or nil if the provider
+	// accepted the request.
+	Err error
+
+	// Recommendation describes the Client setting that works around the
+	// quirk or is empty if no setting is known.
+	Recommendation string
+}
+
+// Report is the result of Check.
+type Report struct {
+	Results []Result
+}
+
+// Quirks returns the results for requests rejected by the provider.
+func (r *Report) Quirks() []Result {
+	var q []Result
+	for _, result := range r.Results {
+		if result.Err != nil {
+			q = append(q, result)
+		}
+	}
+	return q
+}
+
+// WriteTo writes the report in text form to w.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, result := range r.Results {
+		status := "ok"
+		if result.Err != nil {
+			status = "FAIL " + result.Err.Error()
+		}
+		m, err := fmt.Fprintf(w, "%-20s %s\n", result.Name, status)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if result.Err != nil && result.Recommendation != "" {
+			m, err := fmt.Fprintf(w, "%-20s recommendation: %s\n", "", result.Recommendation)
+			n += int64(m)
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// probe is a request sent by Check.
+type probe struct {
+	name   string
+	params url.Values
+
+	// alternate is a variation of the request that a quirky provider
+	// accepts. The recommendation is reported when the provider rejects the
+	// request and accepts the alternate.
+	alternate      url.Values
+	recommendation string
+}
+
+var probes = []probe{
+	{
+		name:   "reserved",
+		params: url.Values{"check": {"!*'() ;/?:@&=+$,"}},
+	},
+	{
+		name:   "unreserved",
+		params: url.Values{"check": {"-._~"}},
+	},
+	{
+		name:   "repeated",
+		params: url.Values{"check": {"b", "a"}},
+	},
+	{
+		name:   "empty",
+		params: url.Values{"check": {""}},
+	},
+	{
+		name:           "unicode-nfd",
+		params:         url.Values{"check": {"u\u0308"}},
+		alternate:      url.Values{"check": {"\u00fc"}},
+		recommendation: "set NormalizeParam to norm.NFC.String",
+	},
+	{
+		name:   "unicode",
+		params: url.Values{"check": {"\u00fc\u65e5\U0001F600"}},
+	},
+}
+
+// ErrBaseline is wrapped by the error returned from Check when the provider
+// rejects a request with no extra parameters. Check the client credentials
+// and the temporary credential request URL when this error is returned.
+var ErrBaseline = errors.New("oauthcheck: provider rejected baseline request")
+
+// Check sends signed temporary credential requests to the provider
+// configured in c. The HTTP client is taken from the context as described in
+// the oauth package documentation.
+func Check(ctx context.Context, c *oauth.Client) (*Report, error) {
+	if _, err := c.RequestTemporaryCredentialsContext(ctx, "oob", nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBaseline, err)
+	}
+	r := &Report{}
+	for _, p := range probes {
+		result := Result{Name: p.name}
+		if _, err := c.RequestTemporaryCredentialsContext(ctx, "oob", p.params); err != nil {
+			result.Err = err
+			if p.alternate != nil {
+				if _, err := c.RequestTemporaryCredentialsContext(ctx, "oob", p.alternate); err == nil {
+					result.Recommendation = p.recommendation
+				}
+			}
+		}
+		r.Results = append(r.Results, result)
+	}
+
+	// Check the default port in the request URL.
+	result := Result{Name: "default-port"}
+	if u, err := url.Parse(c.TemporaryCredentialRequestURI); err == nil && u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		u.Host += ":" + port
+		c2 := *c
+		c2.TemporaryCredentialRequestURI = u.String()
+		if _, err := c2.RequestTemporaryCredentialsContext(ctx, "oob", nil); err != nil {
+			result.Err = err
+			c2.RetainDefaultPort = !c2.RetainDefaultPort
+			if _, err := c2.RequestTemporaryCredentialsContext(ctx, "oob", nil); err == nil {
+				result.Recommendation = fmt.Sprintf("set RetainDefaultPort to %v", c2.RetainDefaultPort)
+			}
+		}
+		r.Results = append(r.Results, result)
+	}
+	return r, nil
+}
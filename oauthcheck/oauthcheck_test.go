@@ -0,0 +1,74 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestCheck(t *testing.T) {
+	// The provider rejects repeated parameters and decomposed unicode.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if len(r.PostForm["check"]) > 1 || r.PostForm.Get("check") == "u\u0308" {
+			http.Error(w, "oauth_problem=signature_invalid", http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, "oauth_token=temp&oauth_token_secret=secret")
+	}))
+	defer ts.Close()
+
+	c := &oauth.Client{TemporaryCredentialRequestURI: ts.URL + "/request_token"}
+	r, err := Check(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Check returned error %v", err)
+	}
+	var names []string
+	for _, q := range r.Quirks() {
+		names = append(names, q.Name)
+		if q.Name == "unicode-nfd" && q.Recommendation == "" {
+			t.Error("no recommendation for unicode-nfd")
+		}
+	}
+	if got, want := strings.Join(names, ","), "repeated,unicode-nfd"; got != want {
+		t.Errorf("quirks %s, want %s", got, want)
+	}
+	var buf bytes.Buffer
+	r.WriteTo(&buf)
+	if !strings.Contains(buf.String(), "norm.NFC.String") {
+		t.Errorf("report does not contain recommendation:\n%s", buf.String())
+	}
+}
+
+func TestCheck_Baseline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oauth_problem=consumer_key_unknown", http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	_, err := Check(context.Background(), &oauth.Client{TemporaryCredentialRequestURI: ts.URL})
+	if !errors.Is(err, ErrBaseline) {
+		t.Errorf("Check returned error %v, want %v", err, ErrBaseline)
+	}
+}
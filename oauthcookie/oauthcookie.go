@@ -0,0 +1,133 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package oauthcookie stores OAuth token credentials in an encrypted cookie.
+//
+// The cookie value is encrypted and authenticated with AES-GCM using
+// oauth.EncodeCredentials. The application must keep the key secret and use
+// the same key across all servers that read the cookie.
+package oauthcookie // import "github.com/garyburd/go-oauth/oauthcookie"
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// ErrInvalid is returned by Store.Get when the cookie cannot be decrypted.
+var ErrInvalid = errors.New("oauthcookie: invalid cookie")
+
+// Store stores credentials in a cookie.
+type Store struct {
+	// Key is the AES key used to encrypt the cookie. The key must be 16, 24
+	// or 32 bytes long.
+	Key []byte
+
+	// Name is the name of the cookie. If this field is the empty string, then
+	// "oauth" is used.
+	Name string
+
+	// Path and Domain specify the scope of the cookie. If Path is the empty
+	// string, then "/" is used.
+	Path   string
+	Domain string
+
+	// MaxAge is the cookie Max-Age attribute. If MaxAge is zero, then the
+	// cookie is a session cookie.
+	MaxAge int
+
+	// Insecure specifies that the cookie is sent over plain HTTP connections.
+	// By default, the cookie has the Secure attribute.
+	Insecure bool
+
+	// SameSite is the cookie SameSite attribute. If this field is zero, then
+	// http.SameSiteLaxMode is used.
+	SameSite http.SameSite
+}
+
+func (s *Store) name() string {
+	if s.Name == "" {
+		return "oauth"
+	}
+	return s.Name
+}
+
+// purpose binds the encoded credentials to the cookie name.
+func (s *Store) purpose() string {
+	return "oauthcookie " + s.name()
+}
+
+func (s *Store) cookie(value string, maxAge int) *http.Cookie {
+	c := &http.Cookie{
+		Name:     s.name(),
+		Value:    value,
+		Path:     s.Path,
+		Domain:   s.Domain,
+		MaxAge:   maxAge,
+		Secure:   !s.Insecure,
+		HttpOnly: true,
+		SameSite: s.SameSite,
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	return c
+}
+
+// Set sets the cookie to the encrypted credentials.
+func (s *Store) Set(w http.ResponseWriter, credentials *oauth.Credentials) error {
+	v, err := oauth.EncodeCredentials(s.Key, credentials, s.purpose())
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, s.cookie(v, s.MaxAge))
+	return nil
+}
+
+// Get returns the credentials from the request cookie. Get returns
+// http.ErrNoCookie if the cookie is not present and ErrInvalid if the cookie
+// cannot be decrypted.
+func (s *Store) Get(r *http.Request) (*oauth.Credentials, error) {
+	c, err := r.Cookie(s.name())
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := oauth.DecodeCredentials(s.Key, c.Value, s.purpose())
+	if err == oauth.ErrInvalidEncoding {
+		return nil, ErrInvalid
+	}
+	return credentials, err
+}
+
+// Clear deletes the cookie.
+func (s *Store) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, s.cookie("", -1))
+}
+
+// Handler returns a handler that adds the credentials from the cookie to the
+// request context using oauth.WithCredentials and then calls h. Use
+// oauth.CredentialsFromContext to get the credentials in h. Requests without
+// a valid cookie are passed to h unchanged.
+func (s *Store) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if credentials, err := s.Get(r); err == nil {
+			r = r.WithContext(oauth.WithCredentials(r.Context(), credentials))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
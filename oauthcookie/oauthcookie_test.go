@@ -0,0 +1,93 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthcookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestStore(t *testing.T) {
+	s := &Store{Key: testKey}
+
+	w := httptest.NewRecorder()
+	if err := s.Set(w, &oauth.Credentials{Token: "token", Secret: "secret"}); err != nil {
+		t.Fatalf("Set returned error %v", err)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != "oauth" || !c.Secure || !c.HttpOnly || c.SameSite != http.SameSiteLaxMode || c.Path != "/" {
+		t.Errorf("cookie attributes %+v, want defaults", c)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(c)
+	credentials, err := s.Get(r)
+	if err != nil {
+		t.Fatalf("Get returned error %v", err)
+	}
+	if credentials.Token != "token" || credentials.Secret != "secret" {
+		t.Errorf("Get returned %+v, want token and secret", credentials)
+	}
+
+	other := &Store{Key: []byte("fedcba9876543210fedcba9876543210")}
+	if _, err := other.Get(r); err != ErrInvalid {
+		t.Errorf("Get with other key returned error %v, want %v", err, ErrInvalid)
+	}
+
+	if _, err := s.Get(httptest.NewRequest("GET", "/", nil)); err != http.ErrNoCookie {
+		t.Errorf("Get without cookie returned error %v, want %v", err, http.ErrNoCookie)
+	}
+
+	w = httptest.NewRecorder()
+	s.Clear(w)
+	if c := w.Result().Cookies()[0]; c.MaxAge >= 0 {
+		t.Errorf("Clear set MaxAge %d, want < 0", c.MaxAge)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s := &Store{Key: testKey}
+	w := httptest.NewRecorder()
+	if err := s.Set(w, &oauth.Credentials{Token: "token"}); err != nil {
+		t.Fatalf("Set returned error %v", err)
+	}
+
+	var got *oauth.Credentials
+	h := s.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = oauth.CredentialsFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got == nil || got.Token != "token" {
+		t.Errorf("handler got credentials %+v, want token", got)
+	}
+
+	got = nil
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if got != nil {
+		t.Errorf("handler got credentials %+v, want nil", got)
+	}
+}
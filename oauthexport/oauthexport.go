@@ -0,0 +1,291 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package oauthexport exports and imports credentials as JSON lines.
+//
+// Use the package to migrate credentials between storage backends. Export
+// the credentials from the old store with a Writer and import them into the
+// new store with a Reader:
+//
+//	w, err := oauthexport.NewWriter(f, key)
+//	for _, u := range users {
+//		err := w.Write(&oauthexport.Record{ID: u.ID, Kind: oauthexport.Token, Credentials: u.Credentials})
+//	}
+//	err = w.Close()
+//
+// If the key is not nil, then each line is encrypted and authenticated with
+// AES-GCM using oauth.EncodeValue. The key must be 16, 24 or 32 bytes long.
+// The first line is a header with a random export ID. The export ID and the
+// position of each record are authenticated, and Close writes an
+// authenticated trailer with the number of records. The Reader rejects
+// encrypted exports with reordered, dropped or truncated records and records
+// from other exports. If the key is nil, then each line is a JSON object.
+package oauthexport // import "github.com/garyburd/go-oauth/oauthexport"
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// Kind is the kind of credentials in a record.
+type Kind string
+
+const (
+	// Temporary credentials are also known as request tokens.
+	Temporary Kind = "temporary"
+
+	// Token credentials are also known as access tokens.
+	Token Kind = "token"
+)
+
+// Record is an exported set of credentials.
+type Record struct {
+	// ID is the application's key for the credentials, for example a user
+	// ID.
+	ID string `json:"id"`
+
+	// Kind is the kind of credentials.
+	Kind Kind `json:"kind"`
+
+	// Credentials are the credentials.
+	Credentials oauth.Credentials `json:"credentials"`
+
+	// ConsumerKey is the client credentials token for the credentials. Set
+	// this field when the store holds credentials for more than one client.
+	ConsumerKey string `json:"consumer_key,omitempty"`
+
+	// SessionHandle is the oauth_session_handle for renewing the
+	// credentials.
+	SessionHandle string `json:"session_handle,omitempty"`
+
+	// Expires is the time that the credentials expire or the zero time if
+	// the expiration time is not known.
+	Expires time.Time `json:"expires"`
+}
+
+// ErrInvalid is returned by Reader.Read when a line cannot be decrypted or
+// decoded.
+var ErrInvalid = errors.New("oauthexport: invalid record")
+
+// ErrTruncated is returned by Reader.Read when an encrypted export ends
+// without a valid header and trailer.
+var ErrTruncated = errors.New("oauthexport: missing trailer")
+
+// headerPurpose authenticates the encrypted header.
+const headerPurpose = "oauthexport header"
+
+// recordPurpose returns the purpose that authenticates the export ID and
+// position of the encrypted record n.
+func recordPurpose(id string, n uint64) string {
+	return fmt.Sprintf("oauthexport record %s %d", id, n)
+}
+
+// trailerPurpose returns the purpose that authenticates the export ID of the
+// encrypted trailer.
+func trailerPurpose(id string) string {
+	return "oauthexport trailer " + id
+}
+
+func checkKey(key []byte) error {
+	if key == nil {
+		return nil
+	}
+	_, err := aes.NewCipher(key)
+	return err
+}
+
+// Writer writes records.
+type Writer struct {
+	w      *bufio.Writer
+	key    []byte
+	id     string
+	n      uint64
+	closed bool
+}
+
+// NewWriter returns a writer that writes records to w. If key is not nil,
+// then the records are encrypted with the key.
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	ew := &Writer{w: bufio.NewWriter(w), key: key}
+	if key != nil {
+		var id [16]byte
+		if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+			return nil, err
+		}
+		ew.id = hex.EncodeToString(id[:])
+		v, err := oauth.EncodeValue(key, []byte(ew.id), headerPurpose)
+		if err != nil {
+			return nil, err
+		}
+		if err := ew.writeLine([]byte(v)); err != nil {
+			return nil, err
+		}
+	}
+	return ew, nil
+}
+
+// Write writes a record.
+func (w *Writer) Write(r *Record) error {
+	if w.closed {
+		return errors.New("oauthexport: write after close")
+	}
+	switch r.Kind {
+	case Temporary, Token:
+	default:
+		return fmt.Errorf("oauthexport: unknown kind %q", r.Kind)
+	}
+	p, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if w.key != nil {
+		v, err := oauth.EncodeValue(w.key, p, recordPurpose(w.id, w.n))
+		if err != nil {
+			return err
+		}
+		p = []byte(v)
+	}
+	if err := w.writeLine(p); err != nil {
+		return err
+	}
+	w.n++
+	return nil
+}
+
+func (w *Writer) writeLine(p []byte) error {
+	if _, err := w.w.Write(p); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Close writes the trailer for encrypted exports and flushes buffered data
+// to the underlying writer. Call Close after the last record is written.
+// Close does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.key != nil {
+		var count [8]byte
+		binary.BigEndian.PutUint64(count[:], w.n)
+		v, err := oauth.EncodeValue(w.key, count[:], trailerPurpose(w.id))
+		if err != nil {
+			return err
+		}
+		if err := w.writeLine([]byte(v)); err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+// Reader reads records.
+type Reader struct {
+	s       *bufio.Scanner
+	key     []byte
+	id      string
+	line    int
+	n       uint64
+	trailer bool
+}
+
+// NewReader returns a reader that reads records from r. The key must be the
+// key used to write the records.
+func NewReader(r io.Reader, key []byte) (*Reader, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	return &Reader{s: s, key: key}, nil
+}
+
+// Read returns the next record. Read returns io.EOF when there are no more
+// records. Empty lines are skipped. For encrypted exports, Read returns
+// ErrTruncated if the input ends before the trailer written by
+// Writer.Close.
+func (r *Reader) Read() (*Record, error) {
+	for r.s.Scan() {
+		r.line++
+		p := r.s.Bytes()
+		if len(p) == 0 {
+			continue
+		}
+		if r.trailer {
+			return nil, fmt.Errorf("%w on line %d: record after trailer", ErrInvalid, r.line)
+		}
+		if r.key != nil && r.id == "" {
+			id, err := oauth.DecodeValue(r.key, string(p), headerPurpose)
+			if err != nil || len(id) == 0 {
+				return nil, fmt.Errorf("%w on line %d: invalid header", ErrInvalid, r.line)
+			}
+			r.id = string(id)
+			continue
+		}
+		if r.key != nil {
+			v := string(p)
+			var err error
+			p, err = oauth.DecodeValue(r.key, v, recordPurpose(r.id, r.n))
+			if err != nil {
+				count, err := oauth.DecodeValue(r.key, v, trailerPurpose(r.id))
+				if err != nil || len(count) != 8 {
+					return nil, fmt.Errorf("%w on line %d", ErrInvalid, r.line)
+				}
+				if binary.BigEndian.Uint64(count) != r.n {
+					return nil, fmt.Errorf("%w on line %d: trailer count does not match", ErrInvalid, r.line)
+				}
+				r.trailer = true
+				continue
+			}
+			r.n++
+		}
+		var rec Record
+		if err := json.Unmarshal(p, &rec); err != nil {
+			return nil, fmt.Errorf("%w on line %d: %v", ErrInvalid, r.line, err)
+		}
+		switch rec.Kind {
+		case Temporary, Token:
+		default:
+			return nil, fmt.Errorf("%w on line %d: unknown kind %q", ErrInvalid, r.line, rec.Kind)
+		}
+		return &rec, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+	if r.key != nil && !r.trailer {
+		return nil, ErrTruncated
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthexport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+var testRecords = []*Record{
+	{ID: "1", Kind: Token, Credentials: oauth.Credentials{Token: "t1", Secret: "s1"}, SessionHandle: "h", Expires: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{ID: "2", Kind: Temporary, Credentials: oauth.Credentials{Token: "t2", Secret: "s2"}, ConsumerKey: "ck"},
+}
+
+func roundTrip(t *testing.T, key []byte) string {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range testRecords {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	exported := buf.String()
+
+	r, err := NewReader(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*Record
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, rec)
+	}
+	if !reflect.DeepEqual(got, testRecords) {
+		t.Errorf("records %+v, want %+v", got, testRecords)
+	}
+	return exported
+}
+
+func TestRoundTrip(t *testing.T) {
+	exported := roundTrip(t, nil)
+	if !strings.Contains(exported, `"kind":"temporary"`) {
+		t.Errorf("plain export %q does not contain kind", exported)
+	}
+
+	key := []byte("0123456789abcdef")
+	exported = roundTrip(t, key)
+	if strings.Contains(exported, `"`) {
+		t.Errorf("encrypted export contains JSON: %q", exported)
+	}
+
+	r, _ := NewReader(strings.NewReader(exported), []byte("fedcba9876543210"))
+	if _, err := r.Read(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Read with wrong key returned %v, want ErrInvalid", err)
+	}
+}
+
+func TestInvalidKind(t *testing.T) {
+	w, _ := NewWriter(io.Discard, nil)
+	if err := w.Write(&Record{ID: "1"}); err == nil {
+		t.Error("Write with empty kind returned nil error")
+	}
+	r, _ := NewReader(strings.NewReader(`{"id":"1","kind":"other"}`+"\n"), nil)
+	if _, err := r.Read(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Read with unknown kind returned %v, want ErrInvalid", err)
+	}
+}
+
+func TestTampering(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	lines := strings.SplitAfter(roundTrip(t, key), "\n")
+	lines = lines[:len(lines)-1] // header, two records and trailer
+	other := strings.SplitAfter(roundTrip(t, key), "\n")
+
+	for _, tt := range []struct {
+		name  string
+		lines []string
+		want  error
+	}{
+		{"reordered", []string{lines[0], lines[2], lines[1], lines[3]}, ErrInvalid},
+		{"first dropped", []string{lines[0], lines[2], lines[3]}, ErrInvalid},
+		{"last dropped", []string{lines[0], lines[1], lines[3]}, ErrInvalid},
+		{"header dropped", []string{lines[1], lines[2], lines[3]}, ErrInvalid},
+		{"trailer dropped", []string{lines[0], lines[1], lines[2]}, ErrTruncated},
+		{"record after trailer", []string{lines[0], lines[1], lines[2], lines[3], lines[2]}, ErrInvalid},
+		{"record from other export", []string{lines[0], lines[1], other[2], lines[3]}, ErrInvalid},
+		{"trailer from other export", []string{lines[0], lines[1], lines[2], other[3]}, ErrInvalid},
+	} {
+		r, _ := NewReader(strings.NewReader(strings.Join(tt.lines, "")), key)
+		var err error
+		for err == nil {
+			_, err = r.Read()
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: Read returned %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestClose_Empty(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, key)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(testRecords[0]); err == nil {
+		t.Error("Write after Close returned nil error")
+	}
+	r, _ := NewReader(&buf, key)
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read returned %v, want io.EOF", err)
+	}
+	r, _ = NewReader(strings.NewReader(""), key)
+	if _, err := r.Read(); err != ErrTruncated {
+		t.Errorf("Read of empty input returned %v, want %v", err, ErrTruncated)
+	}
+}
@@ -0,0 +1,544 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package oauthtest records signed requests to a provider and replays them in
+// tests.
+//
+// A Recorder is an http.RoundTripper that saves requests and responses. Run
+// the integration once against the provider with the recorder and save the
+// interactions to a file:
+//
+//	rec := &oauthtest.Recorder{}
+//	httpClient := &http.Client{Transport: rec}
+//	// Use httpClient with the oauth.Client methods.
+//	rec.Save("testdata/provider.json")
+//
+// A Replayer returns the saved responses and checks that each request matches
+// the saved request. Pin the client nonce and timestamp to the saved values so
+// that the signatures match:
+//
+//	rep, err := oauthtest.Load("testdata/provider.json")
+//	rep.Pin(&oauthClient)
+//	httpClient := &http.Client{Transport: rep}
+//
+// Cookies, xAuth passwords, PLAINTEXT signatures and token secrets in form
+// encoded and JSON responses are redacted from recordings. The signature of a
+// request signed with a redacted token secret is also redacted. Signatures are
+// redacted from the Authorization header, the URL query and form encoded
+// bodies sent with oauth.ParamMethodBody. Authorization headers with a
+// scheme other than OAuth, such as the bearer and basic authorization set by
+// oauth.Client.HostAuthorization, are replaced with Redacted. The replayer
+// does not check redacted values.
+package oauthtest // import "github.com/garyburd/go-oauth/oauthtest"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// Redacted replaces secrets in recordings.
+const Redacted = "REDACTED"
+
+// Interaction is a recorded request and response.
+type Interaction struct {
+	Method         string
+	URL            string
+	Header         http.Header
+	Body           string
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   string
+}
+
+// Recorder is an http.RoundTripper that records requests and responses.
+type Recorder struct {
+	// Transport sends the requests. If this field is nil, then
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu             sync.Mutex
+	interactions   []Interaction
+	redactedTokens map[string]bool
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	t := rec.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	resp, err := t.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	header := cloneHeader(req.Header)
+	header.Del("Cookie")
+	reqURL := *req.URL
+	reqBody := string(body)
+	if isForm(header) {
+		reqBody = redactParam(reqBody, "x_auth_password")
+	}
+	reqURL.RawQuery = redactParam(reqURL.RawQuery, "x_auth_password")
+	if auth := header.Get("Authorization"); auth != "" && !strings.HasPrefix(auth, "OAuth ") {
+		header.Set("Authorization", Redacted)
+	}
+	p := protocolParams(header, reqURL.String(), reqBody)
+	if p["oauth_signature_method"] == "PLAINTEXT" || rec.redactedTokens[p["oauth_token"]] {
+		if auth := header.Get("Authorization"); auth != "" {
+			header.Set("Authorization", redactSignature(auth))
+		}
+		if isForm(header) {
+			reqBody = redactParam(reqBody, "oauth_signature")
+		}
+		reqURL.RawQuery = redactParam(reqURL.RawQuery, "oauth_signature")
+	}
+	responseHeader := cloneHeader(resp.Header)
+	responseHeader.Del("Set-Cookie")
+	rec.interactions = append(rec.interactions, Interaction{
+		Method:         req.Method,
+		URL:            reqURL.String(),
+		Header:         header,
+		Body:           reqBody,
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: responseHeader,
+		ResponseBody:   rec.redactTokenSecret(resp.Header, string(respBody)),
+	})
+	return resp, nil
+}
+
+// redactTokenSecret redacts the oauth_token_secret parameter in a form
+// encoded or JSON credentials response and remembers the token so that
+// signatures using the secret are also redacted. The response is JSON when
+// the Content-Type header specifies JSON as in the oauth package.
+func (rec *Recorder) redactTokenSecret(header http.Header, body string) string {
+	if isJSON(header) {
+		return rec.redactJSONTokenSecret(body)
+	}
+	parts := strings.Split(body, "&")
+	token := ""
+	redacted := false
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, "oauth_token="):
+			token = part[len("oauth_token="):]
+		case strings.HasPrefix(part, "oauth_token_secret="):
+			parts[i] = "oauth_token_secret=" + Redacted
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	if rec.redactedTokens == nil {
+		rec.redactedTokens = make(map[string]bool)
+	}
+	rec.redactedTokens[oauthDecode(token)] = true
+	return strings.Join(parts, "&")
+}
+
+// redactJSONTokenSecret redacts the oauth_token_secret member of a JSON
+// credentials response.
+func (rec *Recorder) redactJSONTokenSecret(body string) string {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &obj); err != nil {
+		return body
+	}
+	if _, ok := obj["oauth_token_secret"]; !ok {
+		return body
+	}
+	obj["oauth_token_secret"], _ = json.Marshal(Redacted)
+	p, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	var token string
+	json.Unmarshal(obj["oauth_token"], &token)
+	if rec.redactedTokens == nil {
+		rec.redactedTokens = make(map[string]bool)
+	}
+	rec.redactedTokens[token] = true
+	return string(p)
+}
+
+// Interactions returns the recorded interactions.
+func (rec *Recorder) Interactions() []Interaction {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Interaction(nil), rec.interactions...)
+}
+
+// Save writes the recorded interactions to a file as JSON.
+func (rec *Recorder) Save(filename string) error {
+	p, err := json.MarshalIndent(rec.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, p, 0666)
+}
+
+// Replayer is an http.RoundTripper that replays recorded interactions.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+}
+
+// NewReplayer returns a replayer for the interactions.
+func NewReplayer(interactions []Interaction) *Replayer {
+	return &Replayer{interactions: interactions}
+}
+
+// Load returns a replayer for the interactions saved in a file by
+// Recorder.Save.
+func Load(filename string) (*Replayer, error) {
+	p, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(p, &interactions); err != nil {
+		return nil, err
+	}
+	return NewReplayer(interactions), nil
+}
+
+// Pin sets the client Timestamp and Nonce fields to return the timestamps
+// and nonces from the recorded requests in order. The recorded timestamps are
+// returned unchanged, so the replay matches a recording made with any
+// Timestamp format.
+func (rep *Replayer) Pin(c *oauth.Client) {
+	var timestamps []string
+	var nonces []string
+	for _, in := range rep.interactions {
+		p := protocolParams(in.Header, in.URL, in.Body)
+		if p["oauth_nonce"] == "" {
+			continue
+		}
+		timestamps = append(timestamps, p["oauth_timestamp"])
+		nonces = append(nonces, p["oauth_nonce"])
+	}
+	var mu sync.Mutex
+	format := c.Timestamp
+	c.Timestamp = func(t time.Time) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(timestamps) == 0 {
+			if format != nil {
+				return format(t)
+			}
+			return strconv.FormatInt(t.Unix(), 10)
+		}
+		ts := timestamps[0]
+		timestamps = timestamps[1:]
+		return ts
+	}
+	c.Nonce = func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(nonces) == 0 {
+			return ""
+		}
+		n := nonces[0]
+		nonces = nonces[1:]
+		return n
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface. RoundTrip returns an
+// error if the request does not match the next recorded request.
+func (rep *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if rep.next >= len(rep.interactions) {
+		return nil, fmt.Errorf("oauthtest: unexpected request %s %s", req.Method, req.URL)
+	}
+	in := rep.interactions[rep.next]
+	rep.next++
+
+	switch {
+	case req.Method != in.Method || !equalURL(req.URL, in.URL):
+		return nil, fmt.Errorf("oauthtest: got request %s %s, want %s %s", req.Method, req.URL, in.Method, in.URL)
+	case string(body) != in.Body && !(isForm(in.Header) && equalForm(string(body), in.Body)):
+		return nil, fmt.Errorf("oauthtest: got body %q for %s %s, want %q", body, req.Method, req.URL, in.Body)
+	}
+	if err := compareAuthorization(req.Header.Get("Authorization"), in.Header.Get("Authorization")); err != nil {
+		return nil, fmt.Errorf("oauthtest: %s %s: %v", req.Method, req.URL, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cloneHeader(in.ResponseHeader),
+		Body:          ioutil.NopCloser(strings.NewReader(in.ResponseBody)),
+		ContentLength: int64(len(in.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// Done returns an error if a recorded request was not replayed.
+func (rep *Replayer) Done() error {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if rep.next < len(rep.interactions) {
+		in := rep.interactions[rep.next]
+		return fmt.Errorf("oauthtest: request %s %s not sent", in.Method, in.URL)
+	}
+	return nil
+}
+
+// compareAuthorization compares the OAuth parameters in an Authorization
+// header with the recorded header. The signature is not compared when the
+// recorded signature is redacted. A redacted header matches any header with
+// a scheme other than OAuth.
+func compareAuthorization(got, want string) error {
+	if want == Redacted {
+		if got == "" || strings.HasPrefix(got, "OAuth ") {
+			return fmt.Errorf("got Authorization %q, want non-OAuth authorization", got)
+		}
+		return nil
+	}
+	g := parseAuthorization(got)
+	w := parseAuthorization(want)
+	if w["oauth_signature"] == Redacted {
+		delete(g, "oauth_signature")
+		delete(w, "oauth_signature")
+	}
+	for k, v := range w {
+		if g[k] != v {
+			return fmt.Errorf("got %s %q, want %q", k, g[k], v)
+		}
+	}
+	for k, v := range g {
+		if _, ok := w[k]; !ok {
+			return fmt.Errorf("unexpected parameter %s %q", k, v)
+		}
+	}
+	return nil
+}
+
+// protocolParams returns the decoded OAuth protocol parameters of a request
+// from the Authorization header, the URL query and the form encoded body.
+func protocolParams(header http.Header, rawURL, body string) map[string]string {
+	p := parseAuthorization(header.Get("Authorization"))
+	var forms []url.Values
+	if u, err := url.Parse(rawURL); err == nil {
+		forms = append(forms, u.Query())
+	}
+	if isForm(header) {
+		form, _ := url.ParseQuery(body)
+		forms = append(forms, form)
+	}
+	for _, form := range forms {
+		for k, v := range form {
+			if strings.HasPrefix(k, "oauth_") {
+				p[k] = v[0]
+			}
+		}
+	}
+	return p
+}
+
+// isForm returns true if the header describes a form encoded body.
+func isForm(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "application/x-www-form-urlencoded")
+}
+
+// isJSON returns true if the header describes a JSON body.
+func isJSON(header http.Header) bool {
+	mt, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// redactParam replaces the values of the parameter key in the form encoded
+// string s. The order of the parameters is not changed.
+func redactParam(s, key string) string {
+	parts := strings.Split(s, "&")
+	for i, part := range parts {
+		if strings.HasPrefix(part, key+"=") {
+			parts[i] = key + "=" + Redacted
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// equalURL returns true if got matches the recorded URL want. Query
+// parameters are compared with equalForm.
+func equalURL(got *url.URL, want string) bool {
+	if got.String() == want {
+		return true
+	}
+	w, err := url.Parse(want)
+	if err != nil {
+		return false
+	}
+	g := *got
+	g.RawQuery = w.RawQuery
+	return g.String() == want && equalForm(got.RawQuery, w.RawQuery)
+}
+
+// equalForm returns true if the form encoded strings got and want contain
+// the same parameters. A parameter that is redacted in want matches any
+// value.
+func equalForm(got, want string) bool {
+	g, err := url.ParseQuery(got)
+	if err != nil {
+		return false
+	}
+	w, err := url.ParseQuery(want)
+	if err != nil || len(g) != len(w) {
+		return false
+	}
+	for k, wv := range w {
+		gv, ok := g[k]
+		if !ok || len(gv) != len(wv) {
+			return false
+		}
+		for i := range wv {
+			if wv[i] != Redacted && wv[i] != gv[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseAuthorization returns the decoded parameters in an OAuth Authorization
+// header. The parameters can be separated by any characters that are not
+// allowed in a parameter name to support a custom oauth.HeaderStyle
+// Separator. Quoted values can contain escaped characters as in the realm
+// parameter.
+func parseAuthorization(s string) map[string]string {
+	p := make(map[string]string)
+	if !strings.HasPrefix(s, "OAuth ") {
+		return p
+	}
+	s = s[len("OAuth "):]
+	for {
+		s = strings.TrimLeftFunc(s, isSeparator)
+		i := strings.IndexByte(s, '=')
+		if i <= 0 {
+			return p
+		}
+		key := strings.TrimSpace(s[:i])
+		s = s[i+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			var buf []byte
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				buf = append(buf, s[i])
+			}
+			value = string(buf)
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else {
+			i := strings.IndexFunc(s, isSeparator)
+			if i < 0 {
+				i = len(s)
+			}
+			value, s = s[:i], s[i:]
+		}
+		if key == "realm" {
+			p[key] = value
+		} else {
+			p[key] = oauthDecode(value)
+		}
+	}
+}
+
+// isSeparator returns true if r is not allowed in a parameter name.
+func isSeparator(r rune) bool {
+	return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' || r == '-' || r == '.')
+}
+
+// redactSignature replaces the signature in an Authorization header.
+func redactSignature(s string) string {
+	const key = `oauth_signature="`
+	i := strings.Index(s, key)
+	if i < 0 {
+		return s
+	}
+	i += len(key)
+	j := strings.IndexByte(s[i:], '"')
+	if j < 0 {
+		return s
+	}
+	return s[:i] + Redacted + s[i+j:]
+}
+
+// oauthDecode decodes a percent encoded string. The string is returned
+// unchanged if it is not valid.
+func oauthDecode(s string) string {
+	if d, err := url.PathUnescape(s); err == nil {
+		return d
+	}
+	return s
+}
+
+func cloneHeader(h http.Header) http.Header {
+	h2 := make(http.Header, len(h))
+	for k, v := range h {
+		h2[k] = append([]string(nil), v...)
+	}
+	return h2
+}
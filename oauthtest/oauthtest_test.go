@@ -0,0 +1,372 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package oauthtest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/request_token":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+			io.WriteString(w, "oauth_token=temp&oauth_token_secret=tempsecret")
+		case "/json_token":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"oauth_token": "jsontemp", "oauth_token_secret": "jsonsecret"}`)
+		case "/access_token":
+			io.WriteString(w, "oauth_token=access&oauth_token_secret=accesssecret")
+		case "/api":
+			io.WriteString(w, `{"id": 1}`)
+		}
+	}))
+}
+
+// run signs and sends a temporary credentials request and an API request
+// signed with the temporary credentials.
+func run(c *oauth.Client, rt http.RoundTripper) error {
+	httpClient := &http.Client{Transport: rt}
+	cred, err := c.RequestTemporaryCredentials(httpClient, "http://example.com/callback", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Get(httpClient, &oauth.Credentials{Token: "token", Secret: "tokensecret"}, c.TokenRequestURI, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	resp, err = c.Get(httpClient, cred, c.TokenRequestURI, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	newClient := func() *oauth.Client {
+		return &oauth.Client{
+			Credentials:                   oauth.Credentials{Token: "key", Secret: "secret"},
+			TemporaryCredentialRequestURI: ts.URL + "/request_token",
+			TokenRequestURI:               ts.URL + "/api",
+		}
+	}
+
+	rec := &Recorder{}
+	if err := run(newClient(), rec); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	interactions := rec.Interactions()
+	if len(interactions) != 3 {
+		t.Fatalf("recorded %d interactions, want 3", len(interactions))
+	}
+	if s := interactions[0].ResponseBody; strings.Contains(s, "tempsecret") {
+		t.Errorf("token secret not redacted from %q", s)
+	}
+	if h := interactions[0].ResponseHeader; h.Get("Set-Cookie") != "" {
+		t.Errorf("cookie not redacted from %v", h)
+	}
+	if auth := interactions[1].Header.Get("Authorization"); strings.Contains(auth, Redacted) {
+		t.Errorf("signature redacted from %s", auth)
+	}
+	if auth := interactions[2].Header.Get("Authorization"); !strings.Contains(auth, `oauth_signature="`+Redacted+`"`) {
+		t.Errorf("signature with redacted secret not redacted from %s", auth)
+	}
+
+	filename := filepath.Join(t.TempDir(), "recording.json")
+	if err := rec.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+
+	rep, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newClient()
+	rep.Pin(c)
+	if err := run(c, rep); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+	if err := rep.Done(); err != nil {
+		t.Error(err)
+	}
+
+	rep = NewReplayer(interactions)
+	if err := run(newClient(), rep); err == nil {
+		t.Error("replay without pinned nonce and timestamp did not return error")
+	}
+}
+
+func TestRecordReplay_HeaderStyle(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	newClient := func() *oauth.Client {
+		return &oauth.Client{
+			Credentials:                   oauth.Credentials{Token: "key", Secret: "secret"},
+			TemporaryCredentialRequestURI: ts.URL + "/request_token",
+			TokenRequestURI:               ts.URL + "/api",
+			HeaderStyle:                   oauth.HeaderStyle{Realm: `http://example.com/, "api"`, Separator: "; "},
+		}
+	}
+	rec := &Recorder{}
+	if err := run(newClient(), rec); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	interactions := rec.Interactions()
+	if auth := interactions[2].Header.Get("Authorization"); !strings.Contains(auth, `oauth_signature="`+Redacted+`"`) {
+		t.Errorf("signature with redacted secret not redacted from %s", auth)
+	}
+	rep := NewReplayer(interactions)
+	c := newClient()
+	rep.Pin(c)
+	if err := run(c, rep); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+
+	// A changed realm does not match.
+	rep = NewReplayer(interactions)
+	c = newClient()
+	rep.Pin(c)
+	c.HeaderStyle.Realm = "other"
+	if err := run(c, rep); err == nil {
+		t.Error("replay with changed realm did not return error")
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	for _, tt := range []struct {
+		header string
+		want   map[string]string
+	}{
+		{`OAuth oauth_token="a%2Bb", oauth_nonce="1"`, map[string]string{"oauth_token": "a+b", "oauth_nonce": "1"}},
+		{`OAuth oauth_token="t";oauth_nonce="1"`, map[string]string{"oauth_token": "t", "oauth_nonce": "1"}},
+		{`OAuth realm="a, \"b\" \\c",oauth_token=t`, map[string]string{"realm": `a, "b" \c`, "oauth_token": "t"}},
+		{`Basic abc`, map[string]string{}},
+	} {
+		got := parseAuthorization(tt.header)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseAuthorization(%q) = %q, want %q", tt.header, got, tt.want)
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("parseAuthorization(%q) = %q, want %q", tt.header, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRecordReplay_MillisecondTimestamp(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	newClient := func() *oauth.Client {
+		return &oauth.Client{
+			Credentials:                   oauth.Credentials{Token: "key", Secret: "secret"},
+			TemporaryCredentialRequestURI: ts.URL + "/request_token",
+			TokenRequestURI:               ts.URL + "/api",
+			Timestamp:                     oauth.MillisecondTimestamp,
+		}
+	}
+	rec := &Recorder{}
+	if err := run(newClient(), rec); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	rep := NewReplayer(rec.Interactions())
+	c := newClient()
+	rep.Pin(c)
+	if err := run(c, rep); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+	if err := rep.Done(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordReplay_ParamMethodBody(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	newClient := func() *oauth.Client {
+		return &oauth.Client{
+			Credentials:     oauth.Credentials{Token: "key", Secret: "clientsecret"},
+			SignatureMethod: oauth.PLAINTEXT,
+			ParamMethod:     oauth.ParamMethodBody,
+		}
+	}
+	post := func(c *oauth.Client, rt http.RoundTripper, status string) error {
+		resp, err := c.Post(&http.Client{Transport: rt}, &oauth.Credentials{Token: "token", Secret: "tokensecret"},
+			ts.URL+"/api", url.Values{"status": {status}})
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	rec := &Recorder{}
+	if err := post(newClient(), rec, "hello"); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	interactions := rec.Interactions()
+	body := interactions[0].Body
+	if strings.Contains(body, "clientsecret") || strings.Contains(body, "tokensecret") {
+		t.Errorf("PLAINTEXT signature not redacted from body %q", body)
+	}
+	if !strings.Contains(body, "oauth_signature="+Redacted) {
+		t.Errorf("body %q does not contain redacted signature", body)
+	}
+
+	rep := NewReplayer(interactions)
+	c := newClient()
+	rep.Pin(c)
+	if err := post(c, rep, "hello"); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+
+	// The protocol parameters in the body are compared.
+	rep = NewReplayer(interactions)
+	c = newClient()
+	c.Credentials.Token = "otherkey"
+	if err := post(c, rep, "hello"); err == nil {
+		t.Error("replay with changed consumer key did not return error")
+	}
+	rep = NewReplayer(interactions)
+	if err := post(newClient(), rep, "goodbye"); err == nil {
+		t.Error("replay with changed form did not return error")
+	}
+}
+
+func TestRecorder_Redact(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	c := &oauth.Client{
+		Credentials:                   oauth.Credentials{Token: "key", Secret: "clientsecret"},
+		TemporaryCredentialRequestURI: ts.URL + "/json_token",
+		TokenRequestURI:               ts.URL + "/access_token",
+	}
+	run := func(c *oauth.Client, rt http.RoundTripper) error {
+		httpClient := &http.Client{Transport: rt}
+		cred, err := c.RequestTemporaryCredentials(httpClient, "oob", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.Get(httpClient, cred, ts.URL+"/api", nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if _, _, err := c.RequestTokenXAuth(httpClient, &oauth.Credentials{}, "user", "hunter2"); err != nil {
+			return err
+		}
+		plain := *c
+		plain.SignatureMethod = oauth.PLAINTEXT
+		signed, err := plain.SignURL(&oauth.Credentials{Token: "token", Secret: "tokensecret"}, http.MethodGet, ts.URL+"/api?x=1")
+		if err != nil {
+			return err
+		}
+		resp, err = httpClient.Get(signed)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	rec := &Recorder{}
+	if err := run(c, rec); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	interactions := rec.Interactions()
+	if len(interactions) != 4 {
+		t.Fatalf("recorded %d interactions, want 4", len(interactions))
+	}
+	for _, in := range interactions {
+		for _, s := range []string{in.URL, in.Body, in.ResponseBody, in.Header.Get("Authorization")} {
+			for _, secret := range []string{"jsonsecret", "accesssecret", "hunter2", "clientsecret", "tokensecret"} {
+				if strings.Contains(s, secret) {
+					t.Errorf("recording of %s %s contains %s: %q", in.Method, in.URL, secret, s)
+				}
+			}
+		}
+	}
+	if auth := interactions[1].Header.Get("Authorization"); !strings.Contains(auth, `oauth_signature="`+Redacted+`"`) {
+		t.Errorf("signature with redacted JSON secret not redacted from %s", auth)
+	}
+
+	rep := NewReplayer(interactions)
+	c2 := *c
+	rep.Pin(&c2)
+	if err := run(&c2, rep); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+	if err := rep.Done(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordReplay_Bearer(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer accesstoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	c := &oauth.Client{HostAuthorization: map[string]oauth.AuthScheme{u.Host: oauth.AuthBearer}}
+	cred := &oauth.Credentials{Token: "accesstoken", Secret: "secret"}
+	run := func(rt http.RoundTripper) error {
+		resp, err := c.Get(&http.Client{Transport: rt}, cred, ts.URL+"/api", nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	rec := &Recorder{Transport: ts.Client().Transport}
+	if err := run(rec); err != nil {
+		t.Fatalf("record returned error %v", err)
+	}
+	interactions := rec.Interactions()
+	if auth := interactions[0].Header.Get("Authorization"); auth != Redacted {
+		t.Errorf("recorded Authorization %q, want %q", auth, Redacted)
+	}
+
+	rep := NewReplayer(interactions)
+	if err := run(rep); err != nil {
+		t.Errorf("replay returned error %v", err)
+	}
+	if err := rep.Done(); err != nil {
+		t.Error(err)
+	}
+}
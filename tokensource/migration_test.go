@@ -0,0 +1,58 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package tokensource
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestMigration(t *testing.T) {
+	c := &oauth.Client{Credentials: oauth.Credentials{Token: "key", Secret: "secret"}}
+	m := &Migration{
+		OAuth1:          New(c, &oauth.Credentials{Token: "token", Secret: "tokensecret"}),
+		OAuth2:          Bearer(func() (string, error) { return "access", nil }),
+		OAuth2Endpoints: []string{"API.example.com/2/", "upload.example.com"},
+	}
+	for _, tt := range []struct {
+		url  string
+		want string
+	}{
+		{"https://api.example.com/2/tweets", "Bearer access"},
+		{"https://api.example.com/1.1/statuses", "OAuth "},
+		{"https://api.example.com/20/x", "OAuth "},
+		{"https://upload.example.com/media", "Bearer access"},
+		{"https://other.example.com/2/", "OAuth "},
+	} {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		got, err := m.AuthorizationHeader(req)
+		if err != nil {
+			t.Errorf("%s: returned error %v", tt.url, err)
+			continue
+		}
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: header %q, want prefix %q", tt.url, got, tt.want)
+		}
+	}
+
+	m.OAuth2 = nil
+	req, _ := http.NewRequest("GET", "https://api.example.com/2/tweets", nil)
+	if _, err := m.AuthorizationHeader(req); err == nil {
+		t.Error("AuthorizationHeader with nil OAuth2 returned nil error")
+	}
+}
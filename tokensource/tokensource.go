@@ -0,0 +1,146 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package tokensource adapts OAuth 1.0a signing to code written against
+// TokenSource style abstractions such as golang.org/x/oauth2.
+//
+// An OAuth 2.0 bearer token is the same for every request, but an OAuth 1.0a
+// signature depends on the request method, URL and form. A TokenSource in
+// this package returns a HeaderProvider that computes the Authorization
+// header for each request. The Transport type adds the header to outgoing
+// requests in the same way as oauth2.Transport:
+//
+//	src := tokensource.New(&oauthClient, tokenCred)
+//	httpClient := &http.Client{Transport: &tokensource.Transport{Source: src}}
+//
+// The Migration type sends requests to some endpoints with an OAuth 2.0
+// bearer token and requests to other endpoints with an OAuth 1.0a signature.
+// Use it to move an application to OAuth 2.0 one endpoint at a time.
+//
+// This package does not import golang.org/x/oauth2.
+package tokensource // import "github.com/garyburd/go-oauth/tokensource"
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+// HeaderProvider returns the Authorization header value for a request.
+type HeaderProvider interface {
+	AuthorizationHeader(req *http.Request) (string, error)
+}
+
+// TokenSource returns a HeaderProvider for signing requests.
+type TokenSource interface {
+	Token() (HeaderProvider, error)
+}
+
+// New returns a TokenSource that signs requests with client c and the
+// token credentials. The credentials can be nil for requests signed with
+// the client credentials only.
+func New(c *oauth.Client, credentials *oauth.Credentials) TokenSource {
+	return &signer{c: c, credentials: credentials}
+}
+
+type signer struct {
+	c           *oauth.Client
+	credentials *oauth.Credentials
+}
+
+// Token returns the signer. The signer does not change.
+func (s *signer) Token() (HeaderProvider, error) {
+	if s.c == nil {
+		return nil, errors.New("tokensource: client not set")
+	}
+	return s, nil
+}
+
+// AuthorizationHeader signs the request. The parameters in the URL query
+// string are included in the signature. If the request body has content type
+// application/x-www-form-urlencoded, then the parameters in the body are also
+// included in the signature and the body is replaced with a reader for the
+// same content.
+func (s *signer) AuthorizationHeader(req *http.Request) (string, error) {
+	form, err := requestForm(req)
+	if err != nil {
+		return "", err
+	}
+	return s.c.AuthorizationHeaderValue(s.credentials, req.Method, req.URL, form)
+}
+
+// requestForm returns the form encoded body of req or nil if the body is not
+// form encoded.
+func requestForm(req *http.Request) (url.Values, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mt != "application/x-www-form-urlencoded" {
+		return nil, nil
+	}
+	p, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(p))
+	return url.ParseQuery(string(p))
+}
+
+// Transport is an http.RoundTripper that adds an Authorization header from
+// Source to each request.
+type Transport struct {
+	// Source provides the Authorization header.
+	Source TokenSource
+
+	// Base is the transport that sends the requests. If this field is nil,
+	// then http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface. The request is
+// cloned before the header is set.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	closeBody := func() {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+	}
+	if t.Source == nil {
+		closeBody()
+		return nil, errors.New("tokensource: Transport's Source is nil")
+	}
+	hp, err := t.Source.Token()
+	if err != nil {
+		closeBody()
+		return nil, err
+	}
+	req2 := req.Clone(req.Context())
+	v, err := hp.AuthorizationHeader(req2)
+	if err != nil {
+		closeBody()
+		return nil, err
+	}
+	req2.Header.Set("Authorization", v)
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req2)
+}
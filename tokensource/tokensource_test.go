@@ -0,0 +1,70 @@
+// Copyright 2026 Gary Burd
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package tokensource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestTransport(t *testing.T) {
+	var gotAuth, gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		p, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(p)
+	}))
+	defer ts.Close()
+
+	c := &oauth.Client{
+		Credentials: oauth.Credentials{Token: "key", Secret: "secret"},
+		Clock:       func() time.Time { return time.Unix(1318622958, 0) },
+		Nonce:       func() string { return "nonce" },
+	}
+	cred := &oauth.Credentials{Token: "token", Secret: "tokensecret"}
+	hc := &http.Client{Transport: &Transport{Source: New(c, cred)}}
+
+	const body = "status=hello+world"
+	req, _ := http.NewRequest("POST", ts.URL+"/update?a=1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	want, _ := c.AuthorizationHeaderValue(cred, "POST", req.URL, url.Values{"status": {"hello world"}})
+	if gotAuth != want {
+		t.Errorf("Authorization\n      %s\nwant: %s", gotAuth, want)
+	}
+	if gotBody != body {
+		t.Errorf("body %q, want %q", gotBody, body)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Transport modified the original request")
+	}
+
+	hc = &http.Client{Transport: &Transport{Source: New(nil, cred)}}
+	if _, err := hc.Get(ts.URL); err == nil {
+		t.Error("Get with nil client returned nil error")
+	}
+}